@@ -0,0 +1,203 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+func projectAddCommand() *cobra.Command {
+	var projectID, conflicts string
+
+	cmd := &cobra.Command{
+		Use:                   "add [OPTIONS] LABEL [SUBPATH]",
+		Short:                 "Apply a package to a tracked project",
+		Args:                  cobra.RangeArgs(1, 2),
+		DisableFlagsInUseLine: true,
+
+		Long: `Applies the package LABEL to a tracked project. The package gets applied to the root of the project, or to
+SUBPATH if given. SUBPATH is relative to the root of the project and has to stay inside of it.`,
+
+		Example: `  proji add docker
+  proji add go-service services/billing
+  proji add --project cf1l3q4bvs0e0m0ibmcg --conflicts skip github-actions`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subpath := ""
+			if len(args) > 1 {
+				subpath = args[1]
+			}
+
+			policy, err := builder.ParseConflictPolicy(conflicts)
+			if err != nil {
+				return err
+			}
+
+			return addPackage(cmd.Context(), args[0], subpath, projectID, policy)
+		},
+	}
+
+	cmd.Flags().StringVarP(&projectID, "project", "p", "",
+		"ID of the project to apply the package to; defaults to the project that contains the current directory")
+	cmd.Flags().StringVar(&conflicts, "conflicts", string(builder.ConflictFail),
+		"How to handle files that already exist (skip, overwrite, backup, prompt, fail)")
+
+	return cmd
+}
+
+// isSubpath checks whether path is equal to or located below base. Both paths are expected to be clean and absolute.
+func isSubpath(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// findProjectContaining returns the tracked project that contains the given directory. If projects are nested, the
+// innermost project is returned. It returns nil if no tracked project contains the directory.
+func findProjectContaining(ctx context.Context, prma projects.Manager, dir string) (*domain.Project, error) {
+	projectList, err := prma.Fetch(ctx)
+	if errors.Is(err, database.ErrBucketNotFound) {
+		return nil, nil // No projects stored yet
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch projects")
+	}
+
+	var match *domain.Project
+	for idx := range projectList {
+		project := &projectList[idx]
+		if !isSubpath(project.Path, dir) {
+			continue
+		}
+
+		if match == nil || len(project.Path) > len(match.Path) {
+			match = project
+		}
+	}
+
+	return match, nil
+}
+
+// loadTargetProject loads the project with the given id. If id is empty, the project that contains the current working
+// directory is loaded.
+func loadTargetProject(ctx context.Context, prma projects.Manager, id string) (*domain.Project, error) {
+	if id != "" {
+		project, err := prma.GetByID(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get project %q", id)
+		}
+
+		return &project, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "get current working directory")
+	}
+
+	project, err := findProjectContaining(ctx, prma, cwd)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, errors.Newf("no tracked project found at %q; use --project to select one", cwd)
+	}
+
+	return project, nil
+}
+
+func addPackage(ctx context.Context, packageLabel, subpath, projectID string, conflicts builder.ConflictPolicy) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	// Find the project that the package should be applied to
+	project, err := loadTargetProject(ctx, prma, projectID)
+	if err != nil {
+		return errors.Wrap(err, "load project")
+	}
+
+	// Resolve the directory that the package gets applied to. The subpath is relative to the project's root, no matter
+	// where proji gets called from, and must not lead outside of the project.
+	target := filepath.Join(project.Path, strings.TrimSpace(subpath))
+	if !isSubpath(project.Path, target) {
+		return errors.Newf("path %q is not located inside project %q (%q)", target, project.Name, project.Path)
+	}
+
+	relPath, err := filepath.Rel(project.Path, target)
+	if err != nil {
+		return errors.Wrapf(err, "get path of %q relative to project", target)
+	}
+	if relPath == "." {
+		relPath = ""
+	}
+
+	logger.Debugf("creating directory %q", target)
+	if err = os.MkdirAll(target, 0o755); err != nil {
+		return errors.Wrapf(err, "create directory %q", target)
+	}
+
+	// Reuse the answers that were given when the project was created; the user only gets prompted for new variables.
+	answers := make(map[string]string, len(project.Answers))
+	for key, value := range project.Answers {
+		answers[key] = value
+	}
+
+	logger.Debugf("applying package %q to project %q at %q", packageLabel, project.ID, target)
+	applied := domain.NewProject(packageLabel, target, project.Name)
+	report, err := buildProject(ctx, applied, &buildOptions{
+		Into:      true,
+		Conflicts: conflicts,
+		Answers:   answers,
+	})
+	if report != nil {
+		if rerr := renderReport(report); rerr != nil {
+			logger.Errorf("Failed to render build report: %v", rerr)
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "apply package %q to project %q", packageLabel, project.Name)
+	}
+
+	// Record the applied package in the project's history
+	history := append(project.History, &domain.ProjectHistoryEntry{
+		Package:   packageLabel,
+		Subpath:   relPath,
+		Manifest:  applied.Manifest,
+		AppliedAt: time.Now(),
+	})
+
+	logger.Debugf("updating project %q", project.ID)
+	err = prma.Update(ctx, &domain.ProjectUpdate{
+		ID:      project.ID,
+		Answers: answers,
+		History: history,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+
+	logger.Infof("Successfully applied package %q to project %q", packageLabel, project.Name)
+
+	return nil
+}
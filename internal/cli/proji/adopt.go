@@ -0,0 +1,179 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/packages"
+)
+
+func projectAdoptCommand() *cobra.Command {
+	var packageLabel, name string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:                   "adopt [OPTIONS] PATH",
+		Short:                 "Track an existing directory as project",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Long: `Tracks an existing directory as project. Without --package, the package is detected by comparing the
+directory trees of the installed packages with the directory; the detected package has to be confirmed.`,
+
+		Example: `  proji project adopt ~/code/legacy-api
+  proji project adopt --package go --name billing .
+  proji project adopt --force ~/code/legacy-api`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return adoptProject(cmd.Context(), args[0], packageLabel, name, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&packageLabel, "package", "p", "",
+		"Label of the package that the project is based on; detected from the directory tree if omitted")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the project; defaults to the name of the directory")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Don't ask for confirmation of the detected package")
+
+	return cmd
+}
+
+func renderTreeMatches(matches []*packages.TreeMatch) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Label", "Name", "Matched", "Score")
+
+	for idx, match := range matches {
+		table.AddRow(
+			idx+1, match.Package.Label, match.Package.Name, fmt.Sprintf("%d/%d", match.Matched, match.Total),
+			fmt.Sprintf("%.0f%%", match.Score*100),
+		)
+	}
+
+	return table.Render()
+}
+
+// detectPackage returns the label of the installed package whose directory tree matches the directory best.
+func detectPackage(ctx context.Context, pama packages.Manager, dir string) (string, error) {
+	logger := simplog.FromContext(ctx)
+
+	logger.Debug("fetching package list")
+	packageList, err := pama.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return "", errors.Wrap(err, "fetch packages")
+	}
+
+	candidates := make([]*domain.Package, 0, len(packageList))
+	for idx := range packageList {
+		candidates = append(candidates, &packageList[idx])
+	}
+
+	logger.Debugf("comparing %d packages against %q", len(candidates), dir)
+	matches, err := packages.MatchTree(afero.NewOsFs(), dir, candidates)
+	if err != nil {
+		return "", errors.Wrap(err, "match packages")
+	}
+	if len(matches) == 0 {
+		return "", errors.Newf("no installed package matches the directory tree of %q; use --package to select one", dir)
+	}
+
+	if err = renderTreeMatches(matches); err != nil {
+		return "", errors.Wrap(err, "render package matches")
+	}
+
+	best := matches[0]
+	logger.Infof("Detected package %q (%d of %d entries found)", best.Package.Label, best.Matched, best.Total)
+
+	return best.Package.Label, nil
+}
+
+func adoptProject(ctx context.Context, path, packageLabel, name string, force bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get managers from session
+	logger.Debug("getting project and package manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+	pama := session.PackageManager
+	if pama == nil {
+		return errors.New("no package manager found")
+	}
+
+	// Get absolute path to the directory; it has to exist
+	path, err := localPathToAbsPath(strings.TrimSpace(path))
+	if err != nil {
+		return errors.Wrapf(err, "get absolute path to %q", path)
+	}
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "stat %q", path)
+	}
+	if !info.IsDir() {
+		return errors.Newf("path %q is not a directory", path)
+	}
+
+	tracked, err := findProjectByPath(ctx, prma, path)
+	if err != nil {
+		return errors.Wrapf(err, "check if project %q is already tracked", path)
+	}
+	if tracked != nil {
+		return errors.Newf("path %q is already tracked as project %q (%s)", path, tracked.Name, tracked.ID)
+	}
+
+	// Make sure the package exists or detect it from the directory tree
+	packageLabel = strings.TrimSpace(packageLabel)
+	if packageLabel != "" {
+		if _, err = pama.GetByLabel(ctx, packageLabel); err != nil {
+			return errors.Wrapf(err, "get package %q", packageLabel)
+		}
+	} else {
+		if packageLabel, err = detectPackage(ctx, pama, path); err != nil {
+			return errors.Wrap(err, "detect package")
+		}
+
+		// Detection is a guess; the user has to agree with it
+		if !force {
+			confirmed, err := promptConfirm(fmt.Sprintf("Adopt %q as project of package %q?", path, packageLabel))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				logger.Info("Aborted; use --package to select a package")
+				return nil
+			}
+		}
+	}
+
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	project := domain.NewProject(packageLabel, path, name)
+
+	logger.Debugf("storing project %q in project manager", project.Name)
+	if err = prma.Store(ctx, project); err != nil {
+		return errors.Wrapf(err, "store project %q in project manager", project.Name)
+	}
+
+	writeProjectMarker(ctx, project)
+
+	logger.Infof("Successfully adopted project %q from %q", project.Name, project.Path)
+
+	return nil
+}
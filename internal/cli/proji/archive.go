@@ -0,0 +1,302 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/projects"
+)
+
+func projectArchiveCommand() *cobra.Command {
+	var keep bool
+	var workspace string
+
+	cmd := &cobra.Command{
+		Use:                   "archive [OPTIONS] [ID]",
+		Short:                 "Pack a project into a compressed archive",
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Long: `Packs the project directory into a compressed tar archive inside of proji's data directory and marks the
+project as archived. Archived projects are ignored by 'proji clean' and can be unpacked again with 'proji project
+restore'. With --keep, the directory stays in place; restoring such a project only reactivates it and
+removes the archive.`,
+
+		Example: `  proji project archive cf1l3q4bvs0e0m0ibmcg
+  proji project archive --keep cf1l3q4bvs0e0m0ibmcg
+  proji project archive --workspace shop`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case workspace != "" && len(args) > 0:
+				return errors.New("--workspace can't be combined with a project ID")
+			case workspace != "":
+				return archiveWorkspace(cmd.Context(), workspace, keep)
+			case len(args) == 0:
+				return errors.New("missing project ID")
+			default:
+				return archiveProject(cmd.Context(), args[0], keep)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep the project directory after archiving it")
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "",
+		"Archive all projects of this workspace that aren't archived yet")
+
+	return cmd
+}
+
+func projectRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "restore [OPTIONS] ID [PATH]",
+		Short:                 "Unpack an archived project",
+		Args:                  cobra.RangeArgs(1, 2),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji project restore cf1l3q4bvs0e0m0ibmcg
+  proji project restore cf1l3q4bvs0e0m0ibmcg ~/code/revived`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 1 {
+				path = args[1]
+			}
+
+			return restoreProject(cmd.Context(), args[0], path)
+		},
+	}
+
+	return cmd
+}
+
+// writeArchive packs the directory into a new archive file at path. A partially written archive is removed again.
+func writeArchive(dir, path string) (size int64, err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, errors.Wrapf(err, "create directory %q", filepath.Dir(path))
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, errors.Wrapf(err, "create archive %q", path)
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = errors.Wrapf(cerr, "close archive %q", path)
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	if err = fsutil.PackDir(file, dir); err != nil {
+		return 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, errors.Wrapf(err, "stat archive %q", path)
+	}
+
+	return info.Size(), nil
+}
+
+// readArchive unpacks the archive file at path into the directory dir.
+func readArchive(path, dir string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "open archive %q", path)
+	}
+	defer func() { _ = file.Close() }()
+
+	return fsutil.UnpackDir(file, dir)
+}
+
+func archiveProject(ctx context.Context, projectID string, keep bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	if session.ProjectManager == nil {
+		return errors.New("no project manager found")
+	}
+
+	return packProject(ctx, session.ProjectManager, session.Config.ArchivesDir(), projectID, keep)
+}
+
+// packProject packs the project into an archive inside of archivesDir and marks it as archived. Unless keep is set,
+// the project directory is removed afterwards.
+func packProject(ctx context.Context, prma projects.Manager, archivesDir, projectID string, keep bool) error {
+	logger := simplog.FromContext(ctx)
+
+	project, err := prma.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "get project %q", projectID)
+	}
+	if project.Archive != nil {
+		return errors.Newf("project %q is already archived at %q", project.Name, project.Archive.Path)
+	}
+	if !keep {
+		if err = checkPurgeable(project.Path); err != nil {
+			return err
+		}
+	}
+
+	archivePath := filepath.Join(archivesDir, project.ID+".tar.gz")
+
+	logger.Infof("Packing project %q into %q", project.Path, archivePath)
+	size, err := writeArchive(project.Path, archivePath)
+	if err != nil {
+		return errors.Wrapf(err, "archive project %q", project.Name)
+	}
+
+	// The record is only marked after the archive was written completely; the directory is only removed after the
+	// record was marked.
+	logger.Debugf("marking project %q as archived", project.ID)
+	err = prma.Update(ctx, &domain.ProjectUpdate{
+		ID:      project.ID,
+		Archive: &domain.ProjectArchive{Path: archivePath, Size: size, ArchivedAt: time.Now()},
+	})
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+
+	if !keep {
+		logger.Debugf("deleting project directory %q", project.Path)
+		if err = os.RemoveAll(project.Path); err != nil {
+			return errors.Wrapf(err, "delete project directory %q", project.Path)
+		}
+	}
+
+	logger.Infof("Successfully archived project %q (%s)", project.Name, fsutil.FormatSize(size))
+
+	return nil
+}
+
+// archiveWorkspace archives all projects of the workspace. Projects that are already archived are skipped; failures
+// are reported, but don't stop the remaining projects from being archived.
+func archiveWorkspace(ctx context.Context, name string, keep bool) error {
+	logger := simplog.FromContext(ctx)
+
+	workspace, err := getWorkspace(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	projectList, err := workspaceProjects(ctx, workspace)
+	if err != nil {
+		return errors.Wrapf(err, "load projects of workspace %q", workspace.Name)
+	}
+
+	failed := 0
+	for _, project := range projectList {
+		if project.Archive != nil {
+			logger.Debugf("project %q is already archived, skipping", project.ID)
+			continue
+		}
+
+		if err = archiveProject(ctx, project.ID, keep); err != nil {
+			logger.Warnf("Failed to archive project %q: %v", project.Name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Newf("failed to archive %d of %d projects of workspace %q", failed, len(projectList),
+			workspace.Name)
+	}
+
+	return nil
+}
+
+func restoreProject(ctx context.Context, projectID, path string) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	return unpackProject(ctx, prma, projectID, path)
+}
+
+// unpackProject unpacks the archive of the project into path, or into its original location if path is empty, and
+// reactivates the project. The archive is removed afterwards.
+func unpackProject(ctx context.Context, prma projects.Manager, projectID, path string) error {
+	logger := simplog.FromContext(ctx)
+
+	project, err := prma.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "get project %q", projectID)
+	}
+	if project.Archive == nil {
+		return errors.Newf("project %q is not archived", project.Name)
+	}
+
+	// Restore to the original location by default; the target must not exist yet
+	if path == "" {
+		path = project.Path
+	}
+	path, err = localPathToAbsPath(strings.TrimSpace(path))
+	if err != nil {
+		return errors.Wrapf(err, "get absolute path to %q", path)
+	}
+	path = filepath.Clean(path)
+
+	// Projects that were archived with --keep still have their directory at the original location. There is nothing
+	// to unpack; the project only gets reactivated. The archive is removed like after any other restore; it's
+	// outdated as soon as the directory changes and would otherwise block archiving the project again.
+	if path == project.Path && fsutil.IsDir(path) {
+		logger.Debugf("reactivating project %q", project.ID)
+		err = prma.Update(ctx, &domain.ProjectUpdate{ID: project.ID, Unarchive: true})
+		if err != nil {
+			return errors.Wrapf(err, "update project %q", project.ID)
+		}
+
+		if err = os.Remove(project.Archive.Path); err != nil {
+			logger.Warnf("Failed to remove archive %q: %v", project.Archive.Path, err)
+		}
+
+		logger.Infof("Successfully restored project %q; its directory was kept", project.Name)
+
+		return nil
+	}
+
+	if _, err = os.Lstat(path); err == nil {
+		return errors.Newf("path %q already exists", path)
+	}
+
+	logger.Infof("Unpacking %q into %q", project.Archive.Path, path)
+	if err = readArchive(project.Archive.Path, path); err != nil {
+		_ = os.RemoveAll(path)
+		return errors.Wrapf(err, "restore project %q", project.Name)
+	}
+
+	logger.Debugf("reactivating project %q", project.ID)
+	err = prma.Update(ctx, &domain.ProjectUpdate{ID: project.ID, Path: path, Unarchive: true})
+	if err != nil {
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+
+	if err = os.Remove(project.Archive.Path); err != nil {
+		logger.Warnf("Failed to remove archive %q: %v", project.Archive.Path, err)
+	}
+
+	logger.Infof("Successfully restored project %q to %q", project.Name, path)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/projects"
+)
+
+// memoryProjects is a projects.Manager that keeps its projects in memory.
+type memoryProjects struct {
+	projects map[string]*domain.Project
+}
+
+var _ projects.Manager = &memoryProjects{}
+
+func (m *memoryProjects) Fetch(_ context.Context) ([]domain.Project, error) {
+	list := make([]domain.Project, 0, len(m.projects))
+	for _, project := range m.projects {
+		list = append(list, *project)
+	}
+
+	return list, nil
+}
+
+func (m *memoryProjects) GetByID(_ context.Context, id string) (domain.Project, error) {
+	project, ok := m.projects[id]
+	if !ok {
+		return domain.Project{}, errors.Newf("project %q not found", id)
+	}
+
+	return *project, nil
+}
+
+func (m *memoryProjects) Store(_ context.Context, project *domain.ProjectAdd) error {
+	m.projects[project.ID] = &domain.Project{ID: project.ID, Name: project.Name, Path: project.Path}
+
+	return nil
+}
+
+func (m *memoryProjects) Update(_ context.Context, update *domain.ProjectUpdate) error {
+	project, ok := m.projects[update.ID]
+	if !ok {
+		return errors.Newf("project %q not found", update.ID)
+	}
+
+	project.ApplyUpdate(update)
+
+	return nil
+}
+
+func (m *memoryProjects) Remove(_ context.Context, id string) error {
+	delete(m.projects, id)
+
+	return nil
+}
+
+func TestArchiveProject_KeepRestoreArchive(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	archivesDir := filepath.Join(root, "archives")
+	projectDir := filepath.Join(root, "app")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatalf("failed to create project directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main"), 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	ctx := context.Background()
+	prma := &memoryProjects{projects: map[string]*domain.Project{
+		"abc": {ID: "abc", Name: "app", Path: projectDir},
+	}}
+
+	// Archive and restore twice; a restored project must be archivable again
+	for round := 1; round <= 2; round++ {
+		if err := packProject(ctx, prma, archivesDir, "abc", true); err != nil {
+			t.Fatalf("round %d: packProject() error = %v", round, err)
+		}
+
+		archive := prma.projects["abc"].Archive
+		if archive == nil {
+			t.Fatalf("round %d: expected project to be archived", round)
+		}
+		if _, err := os.Stat(archive.Path); err != nil {
+			t.Fatalf("round %d: expected archive %q to exist: %v", round, archive.Path, err)
+		}
+		if !fsutil.IsDir(projectDir) {
+			t.Fatalf("round %d: expected project directory to be kept", round)
+		}
+
+		if err := unpackProject(ctx, prma, "abc", ""); err != nil {
+			t.Fatalf("round %d: unpackProject() error = %v", round, err)
+		}
+
+		if prma.projects["abc"].Archive != nil {
+			t.Fatalf("round %d: expected project to be reactivated", round)
+		}
+		if _, err := os.Stat(archive.Path); !os.IsNotExist(err) {
+			t.Fatalf("round %d: expected archive %q to be removed, got %v", round, archive.Path, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package proji
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+type (
+	// projectSpec describes a single project that should be created as part of a batch.
+	projectSpec struct {
+		Package   string            `json:"package" toml:"package"`
+		Path      string            `json:"path" toml:"path"`
+		Name      string            `json:"name,omitempty" toml:"name,omitempty"` // Defaults to the base of the path
+		Values    map[string]string `json:"values,omitempty" toml:"values,omitempty"`
+		Workspace string            `json:"workspace,omitempty" toml:"workspace,omitempty"` // Joined after creation
+	}
+
+	// batchFile is the layout of a batch file. In TOML, projects are given as an array of tables named 'project'; in
+	// JSON, as an array named 'projects'.
+	batchFile struct {
+		Projects []*projectSpec `json:"projects" toml:"project"`
+	}
+
+	// batchResult is the outcome of creating a single project of a batch.
+	batchResult struct {
+		Spec *projectSpec
+		Err  error
+	}
+)
+
+// loadBatchFile reads the project specs from the given TOML or JSON file. Relative project paths are resolved against
+// the directory of the batch file; projects without a name are named after their directory.
+func loadBatchFile(path string) ([]*projectSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read batch file")
+	}
+
+	// Detect file extension and parse accordingly
+	var batch batchFile
+	switch filepath.Ext(path) {
+	case ".toml":
+		err = toml.Unmarshal(data, &batch)
+	case ".json":
+		err = json.Unmarshal(data, &batch)
+	default:
+		return nil, errors.Newf("unsupported batch file type %q; expected .toml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal batch file")
+	}
+
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get absolute path of %q", filepath.Dir(path))
+	}
+
+	for idx, spec := range batch.Projects {
+		spec.Package = strings.TrimSpace(spec.Package)
+		spec.Path = strings.TrimSpace(spec.Path)
+		if spec.Package == "" || spec.Path == "" {
+			return nil, errors.Newf("project #%d: package and path are required", idx+1)
+		}
+
+		if !filepath.IsAbs(spec.Path) {
+			spec.Path = filepath.Join(baseDir, spec.Path)
+		}
+		if spec.Name = strings.TrimSpace(spec.Name); spec.Name == "" {
+			spec.Name = filepath.Base(spec.Path)
+		}
+	}
+
+	return batch.Projects, nil
+}
+
+// specsFromPaths returns one project spec per path, all using the same package labels.
+func specsFromPaths(packageLabels string, paths []string) ([]*projectSpec, error) {
+	specs := make([]*projectSpec, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := localPathToAbsPath(strings.TrimSpace(path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "get absolute path to project %q", path)
+		}
+
+		specs = append(specs, &projectSpec{Package: packageLabels, Path: absPath})
+	}
+
+	return specs, nil
+}
+
+// planProjects prints a dry-run for each of the given specs.
+func planProjects(ctx context.Context, specs []*projectSpec) error {
+	for _, spec := range specs {
+		opts := &buildOptions{Conflicts: builder.ConflictFail, Answers: spec.Values}
+		if err := planProject(ctx, parsePackageLabels(spec.Package), spec.Path, opts); err != nil {
+			return errors.Wrapf(err, "plan project %q", spec.Path)
+		}
+	}
+
+	return nil
+}
+
+// hasPlugins checks whether any of the packages of the given spec run plugins.
+func hasPlugins(ctx context.Context, spec *projectSpec) (bool, error) {
+	pama := cli.SessionFromContext(ctx).PackageManager
+	if pama == nil {
+		return false, errors.New("no package manager found")
+	}
+
+	_package, err := loadPackage(ctx, pama, parsePackageLabels(spec.Package)...)
+	if err != nil {
+		return false, err
+	}
+
+	return _package.Plugins != nil && (len(_package.Plugins.Pre) > 0 || len(_package.Plugins.Post) > 0), nil
+}
+
+// createProject creates a single project of a batch. Prompts are prefixed with the project's name, so that the user
+// can tell them apart when projects are created in parallel.
+func createProject(ctx context.Context, spec *projectSpec) error {
+	packageLabels := parsePackageLabels(spec.Package)
+	if len(packageLabels) == 0 {
+		return errors.New("missing package label")
+	}
+
+	answers := make(map[string]string, len(spec.Values))
+	for key, value := range spec.Values {
+		answers[key] = value
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = filepath.Base(spec.Path)
+	}
+
+	return newProject(ctx, packageLabels[0], spec.Path, &buildOptions{
+		Name:         name,
+		Conflicts:    builder.ConflictFail,
+		Layers:       packageLabels[1:],
+		MissingKeyFn: newTemplateKeyPrompt(filepath.Base(spec.Path)),
+		Answers:      answers,
+		Workspace:    spec.Workspace,
+	})
+}
+
+// createProjects creates a project for each of the given specs and prints a summary of the results. Projects of
+// template-only packages get created concurrently, with at most parallel builds at a time. Plugins are attached to the
+// terminal and may be interactive; packages that run plugins are thus always created one after another.
+func createProjects(ctx context.Context, specs []*projectSpec, parallel int) error {
+	logger := simplog.FromContext(ctx)
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]*batchResult, len(specs))
+	var serial []int
+
+	group := errgroup.Group{}
+	group.SetLimit(parallel)
+
+	for idx, spec := range specs {
+		results[idx] = &batchResult{Spec: spec}
+
+		if parallel > 1 {
+			withPlugins, err := hasPlugins(ctx, spec)
+			if err != nil {
+				results[idx].Err = err
+				continue
+			}
+			if !withPlugins {
+				idx, spec := idx, spec
+				group.Go(func() error {
+					results[idx].Err = createProject(ctx, spec)
+
+					return nil // Failures are collected in the results; don't cancel the other builds
+				})
+				continue
+			}
+		}
+
+		serial = append(serial, idx)
+	}
+
+	_ = group.Wait()
+
+	for _, idx := range serial {
+		results[idx].Err = createProject(ctx, specs[idx])
+	}
+
+	// Print summary
+	failed := 0
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Package", "Path", "Status", "Error")
+	for idx, result := range results {
+		status, errMsg := "created", ""
+		if result.Err != nil {
+			failed++
+			status, errMsg = "failed", result.Err.Error()
+			logger.Debugf("failed to create project %q: %v", result.Spec.Path, result.Err)
+		}
+
+		table.AddRow(idx+1, result.Spec.Package, result.Spec.Path, status, errMsg)
+	}
+
+	if err := table.Render(); err != nil {
+		return errors.Wrap(err, "render summary table")
+	}
+
+	if failed > 0 {
+		return errors.Newf("failed to create %d of %d projects", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package proji
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadBatchFile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		file     string
+		content  string
+		want     func(dir string) []*projectSpec
+		wantErr  bool
+		noCreate bool
+	}{
+		{
+			name: "toml",
+			file: "batch.toml",
+			content: `
+[[project]]
+package = "go,docker"
+path = "services/cart"
+name = "Cart"
+workspace = "shop"
+
+[project.values]
+author = "jane"
+
+[[project]]
+package = " go "
+path = "/srv/billing"
+`,
+			want: func(dir string) []*projectSpec {
+				return []*projectSpec{
+					{
+						Package:   "go,docker",
+						Path:      filepath.Join(dir, "services", "cart"),
+						Name:      "Cart",
+						Values:    map[string]string{"author": "jane"},
+						Workspace: "shop",
+					},
+					{Package: "go", Path: "/srv/billing", Name: "billing"},
+				}
+			},
+		},
+		{
+			name:    "json",
+			file:    "batch.json",
+			content: `{"projects": [{"package": "go", "path": "cart", "values": {"author": "jane"}}]}`,
+			want: func(dir string) []*projectSpec {
+				return []*projectSpec{
+					{Package: "go", Path: filepath.Join(dir, "cart"), Name: "cart", Values: map[string]string{"author": "jane"}},
+				}
+			},
+		},
+		{
+			name:    "default name ignores blank names",
+			file:    "batch.json",
+			content: `{"projects": [{"package": "go", "path": "./apps/cart/", "name": "  "}]}`,
+			want: func(dir string) []*projectSpec {
+				return []*projectSpec{{Package: "go", Path: filepath.Join(dir, "apps", "cart"), Name: "cart"}}
+			},
+		},
+		{
+			name:    "missing package",
+			file:    "batch.toml",
+			content: "[[project]]\npath = \"cart\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing path",
+			file:    "batch.json",
+			content: `{"projects": [{"package": "go"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid content",
+			file:    "batch.json",
+			content: `{"projects": `,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported file type",
+			file:    "batch.yaml",
+			content: "projects: []",
+			wantErr: true,
+		},
+		{
+			name:     "file does not exist",
+			file:     "batch.toml",
+			noCreate: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, tc.file)
+			if !tc.noCreate {
+				if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+					t.Fatalf("failed to write batch file: %v", err)
+				}
+			}
+
+			got, err := loadBatchFile(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("loadBatchFile() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want(dir), got); diff != "" {
+				t.Fatalf("loadBatchFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/projects"
+)
+
+func projectCdCommand() *cobra.Command {
+	var open bool
+
+	cmd := &cobra.Command{
+		Use:                   "cd [OPTIONS] QUERY",
+		Short:                 "Print the path of a project",
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Long: `Resolves a project by its ID, its name or a fuzzy match and prints its path. A program can't change the
+working directory of your shell, so load the shell integration to make 'proji cd' jump into the project:
+
+  eval "$(proji shell-init bash)"`,
+
+		Example: `  proji cd billing-api
+  proji cd --open cf1l3q4bvs0e0m0ibmcg
+  cd "$(proji cd client-a api)"`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cdProject(cmd.Context(), strings.Join(args, " "), open)
+		},
+	}
+
+	// The path is the only thing that may end up on stdout; the shell integration changes into whatever gets printed.
+	cmd.SetOut(os.Stderr)
+
+	cmd.Flags().BoolVarP(&open, "open", "o", false, "Open the project in the configured text editor")
+
+	return cmd
+}
+
+// resolveProject finds the project that the query refers to. The query is matched against project IDs first, then
+// against project names and finally fuzzy-matched against names, paths and descriptions.
+func resolveProject(ctx context.Context, projectList []domain.Project, query string) (*domain.Project, error) {
+	query = strings.TrimSpace(query)
+
+	for idx := range projectList {
+		if projectList[idx].ID == query {
+			return &projectList[idx], nil
+		}
+	}
+
+	var named []*domain.Project
+	for idx := range projectList {
+		if strings.EqualFold(projectList[idx].Name, query) {
+			named = append(named, &projectList[idx])
+		}
+	}
+
+	if len(named) == 1 {
+		return named[0], nil
+	}
+	if len(named) > 1 {
+		paths := make([]string, 0, len(named))
+		for _, project := range named {
+			paths = append(paths, project.ID+" ("+project.Path+")")
+		}
+
+		return nil, errors.Newf("multiple projects are named %q; use one of their IDs: %s", query,
+			strings.Join(paths, ", "))
+	}
+
+	results := projects.Search(projectList, query)
+	if len(results) == 0 {
+		return nil, errors.Newf("no project matches %q", query)
+	}
+
+	simplog.FromContext(ctx).Debugf("fuzzy matched %q to project %q with score %d", query, results[0].Project.Name,
+		results[0].Score)
+
+	return results[0].Project, nil
+}
+
+// openInEditor opens the directory in the given text editor. The editor may include arguments, e.g. 'code --wait'. If
+// no editor is configured, $VISUAL and $EDITOR are tried. The editor's output goes to stderr, so that it doesn't mix
+// with the printed path.
+func openInEditor(ctx context.Context, editor, dir string) error {
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" && runtime.GOOS == "windows" {
+		editor = "notepad.exe"
+	}
+
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return errors.New("no text editor configured; set system.text_editor in the config or $EDITOR")
+	}
+
+	simplog.FromContext(ctx).Debugf("opening %q in %s", dir, editor)
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], dir)...)
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func cdProject(ctx context.Context, query string, open bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return errors.New("no project manager available")
+	}
+
+	logger.Debug("fetching project list")
+	projectList, err := prma.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return errors.Wrap(err, "fetch projects")
+	}
+
+	project, err := resolveProject(ctx, projectList, query)
+	if err != nil {
+		return err
+	}
+	if project.Archive != nil {
+		return errors.Newf("project %q is archived; restore it with 'proji project restore %s'", project.Name,
+			project.ID)
+	}
+	if !doesPathExist(project.Path) {
+		return errors.Newf("path %q of project %q does not exist", project.Path, project.Name)
+	}
+
+	if open {
+		editor := ""
+		if session.Config != nil {
+			editor = session.Config.System.TextEditor
+		}
+
+		if err = openInEditor(ctx, editor, project.Path); err != nil {
+			return errors.Wrapf(err, "open project %q", project.Name)
+		}
+	}
+
+	_, err = fmt.Println(project.Path)
+
+	return err
+}
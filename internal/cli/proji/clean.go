@@ -18,6 +18,9 @@ func projectCleanCommand() *cobra.Command {
 		Args:                  cobra.ExactArgs(0),
 		DisableFlagsInUseLine: true,
 
+		Long: `Removes projects whose path no longer exists. Projects that were moved below one of the configured scan roots
+(projects.scan_roots) are relocated instead.`,
+
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cleanProjects(cmd.Context())
 		},
@@ -44,6 +47,21 @@ func cleanProjects(ctx context.Context) error {
 		return errors.New("no project manager available")
 	}
 
+	// Projects that were merely moved get their paths updated instead of being removed
+	roots, err := scanRoots(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	relocations, err := relocateProjects(ctx, roots, false)
+	if err != nil {
+		return errors.Wrap(err, "relocate projects")
+	}
+	for _, relocation := range relocations {
+		logger.Infof("Relocated project %s (%q) from %q to %q", relocation.Project.Name, relocation.Project.ID,
+			relocation.OldPath, relocation.NewPath)
+	}
+
 	// Call the projects.
 	logger.Debug("fetching project list")
 	projects, err := prma.Fetch(ctx)
@@ -55,15 +73,20 @@ func cleanProjects(ctx context.Context) error {
 	removeCounter := 0
 	logger.Debug("cleaning projects")
 	for _, project := range projects {
+		if project.Archive != nil {
+			logger.Debug("project is archived, skipping")
+			continue // Archived projects have no directory on purpose
+		}
 		if doesPathExist(project.Path) {
 			logger.Debug("project path exists, skipping")
 			continue // Skip if path exists
 		}
 
 		logger.Infof("Removing project %s (%q); last known location: %q", project.Name, project.ID, project.Path)
-		if err = prma.Remove(ctx, project.Path); err != nil {
+		if err = prma.Remove(ctx, project.ID); err != nil {
 			return errors.Wrapf(err, "Failed to remove project %q", project.ID)
 		}
+		forgetWorkspaceMember(ctx, project.ID)
 
 		removeCounter++
 	}
@@ -0,0 +1,58 @@
+package proji
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+// promptConflict asks the user how to handle the already existing file at path.
+func promptConflict(path string) (builder.ConflictPolicy, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+
+	for {
+		_, err := fmt.Printf("   > File %q already exists. [s]kip, [o]verwrite, [b]ackup or [f]ail? ", path)
+		if err != nil {
+			return "", errors.Wrapf(err, "prompt conflict resolution for %q", path)
+		}
+
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			return "", errors.Wrapf(err, "read conflict resolution for %q", path)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "s", "skip":
+			return builder.ConflictSkip, nil
+		case "o", "overwrite":
+			return builder.ConflictOverwrite, nil
+		case "b", "backup":
+			return builder.ConflictBackup, nil
+		case "f", "fail":
+			return builder.ConflictFail, nil
+		}
+	}
+}
+
+// renderReport prints the changes of a build as a table.
+func renderReport(r *builder.Report) error {
+	if r == nil || len(r.Changes) == 0 {
+		fmt.Println("\nNo changes were made")
+		return nil
+	}
+
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Action", "Path", "Backup")
+
+	for idx, change := range r.Changes {
+		table.AddRow(idx+1, change.Action, change.Path, change.Backup)
+	}
+
+	return table.Render()
+}
@@ -0,0 +1,68 @@
+package proji
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+// isPathLike reports whether the argument is meant as a path rather than a plain project name. Absolute paths, paths
+// relative to the home directory or the current directory and anything that contains a path separator count as paths.
+func isPathLike(arg string) bool {
+	if filepath.IsAbs(arg) || arg == "." || arg == ".." || strings.HasPrefix(arg, "~") {
+		return true
+	}
+
+	return strings.ContainsAny(arg, "/"+string(filepath.Separator))
+}
+
+// projectDestination returns the path that a project with the given name gets created at, if the name is not a path
+// itself. The destination pattern of the package wins over the projects root of the config; without either, the name
+// is returned as is and thus resolved against the current working directory.
+//
+// Destination patterns are templates, e.g. '~/code/go/%{{project-name}}%'. The project name is known upfront; other
+// variables are resolved through missingKeyFn. The values of all variables are returned, so that the build doesn't ask
+// for them again.
+func projectDestination(ctx context.Context, packageLabel, name string, missingKeyFn templates.MissingKeyFn,
+) (string, map[string]string, error) {
+	logger := simplog.FromContext(ctx)
+
+	session := cli.SessionFromContext(ctx)
+	pama := session.PackageManager
+	if pama == nil {
+		return "", nil, errors.New("no package manager found")
+	}
+
+	_package, err := pama.GetByLabel(ctx, packageLabel)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "get package %q", packageLabel)
+	}
+
+	if _package.Destination != nil && strings.TrimSpace(*_package.Destination) != "" {
+		logger.Debugf("rendering destination %q of package %q", *_package.Destination, _package.Label)
+
+		tmpl := templates.NewEngine("", "")
+		tmpl.MissingKeyFn = missingKeyFn
+		tmpl.SetValues(map[string]string{"project-name": name})
+
+		path, err := tmpl.ParseToString(ctx, strings.TrimSpace(*_package.Destination))
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "render destination of package %q", _package.Label)
+		}
+
+		return fsutil.ExpandPath(path), tmpl.Values, nil
+	}
+
+	if session.Config != nil && session.Config.Projects.Root != "" {
+		return filepath.Join(fsutil.ExpandPath(session.Config.Projects.Root), name), nil, nil
+	}
+
+	return name, nil, nil
+}
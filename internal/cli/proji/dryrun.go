@@ -0,0 +1,299 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+type (
+	// plannedEntry is a directory or file that would be created by a build.
+	plannedEntry struct {
+		Path            string
+		IsDir           bool
+		Template        string                 // Resolved path to the template file; empty if no template is used
+		TemplateMissing bool                   // Whether the template file could not be found
+		Conflict        builder.ConflictPolicy // How an already existing file would be handled; empty if none exists
+	}
+
+	// plannedPlugin is a plugin that would be run by a build.
+	plannedPlugin struct {
+		Stage string
+		Path  string
+	}
+
+	// projectPlan describes everything a build of a project would do. It is the result of a dry-run and gets
+	// populated without touching the filesystem or the project database.
+	projectPlan struct {
+		Path       string
+		PathExists bool
+		Into       bool // Whether the project would be scaffolded into the existing directory at Path
+		Package    *domain.Package
+		Entries    []*plannedEntry
+		Plugins    []*plannedPlugin
+		Variables  []string
+	}
+
+	// keyRecorder records the keys of all template variables that would be prompted for.
+	keyRecorder struct {
+		keys []string
+		seen map[string]struct{}
+	}
+)
+
+func newKeyRecorder() *keyRecorder {
+	return &keyRecorder{seen: make(map[string]struct{})}
+}
+
+// missingKeyFn records the key and returns a placeholder instead of prompting the user for a value. The placeholder
+// makes it visible in templated paths where a value would be inserted.
+func (r *keyRecorder) missingKeyFn(key string) (string, error) {
+	if _, exists := r.seen[key]; !exists {
+		r.seen[key] = struct{}{}
+		r.keys = append(r.keys, key)
+	}
+
+	return "<" + key + ">", nil
+}
+
+// addDir adds the directory and all of its parents to the plan. Directories that were already added are skipped. This
+// mirrors the implicit directory creation of createEntry.
+func (p *projectPlan) addDir(dir string, seen map[string]struct{}) {
+	var parents []string
+	for ; dir != "." && dir != string(filepath.Separator) && dir != ""; dir = filepath.Dir(dir) {
+		parents = append(parents, dir)
+	}
+
+	// Add parents first, so that the plan lists directories in the order they would be created
+	for i := len(parents) - 1; i >= 0; i-- {
+		if _, exists := seen[parents[i]]; exists {
+			continue
+		}
+
+		seen[parents[i]] = struct{}{}
+		p.Entries = append(p.Entries, &plannedEntry{Path: parents[i], IsDir: true})
+	}
+}
+
+func planPlugins(stage string, plugins []*domain.Plugin, pluginsDir string) []*plannedPlugin {
+	planned := make([]*plannedPlugin, 0, len(plugins))
+	for _, plugin := range plugins {
+		path := plugin.Path
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(pluginsDir, path)
+		}
+
+		planned = append(planned, &plannedPlugin{Stage: stage, Path: path})
+	}
+
+	return planned
+}
+
+// buildPlan plans the build of a project from the package at path. Like the build, it uses the answers, the into mode
+// and the conflict policy of the given options; only variables without an answer are listed as prompted for.
+func buildPlan(
+	ctx context.Context, _package *domain.Package, path, templatesDir, pluginsDir string, opts *buildOptions,
+) (*projectPlan, error) {
+	logger := simplog.FromContext(ctx)
+
+	if opts == nil {
+		opts = &buildOptions{Conflicts: builder.ConflictFail}
+	}
+
+	plan := &projectPlan{
+		Path:       path,
+		PathExists: doesPathExist(path),
+		Into:       opts.Into,
+		Package:    _package,
+	}
+
+	recorder := newKeyRecorder()
+	tmpl := templates.NewEngine("", "")
+	tmpl.MissingKeyFn = recorder.missingKeyFn
+	tmpl.SetValues(opts.Answers)
+
+	if _package.Plugins != nil {
+		plan.Plugins = append(plan.Plugins, planPlugins("pre", _package.Plugins.Pre, pluginsDir)...)
+	}
+
+	if _package.DirTree != nil {
+		seenDirs := make(map[string]struct{})
+
+		for _, entry := range _package.DirTree.Entries {
+			// Like the build, fall back to the raw path if it's not a valid template
+			entryPath, err := tmpl.ParseToString(ctx, entry.Path)
+			if err != nil {
+				logger.Debugf("template path %q is not a template string", entry.Path)
+				entryPath = entry.Path
+			}
+			entryPath = filepath.Clean(entryPath)
+
+			if entry.IsDir {
+				plan.addDir(entryPath, seenDirs)
+				continue
+			}
+
+			plan.addDir(filepath.Dir(entryPath), seenDirs)
+			planned := &plannedEntry{Path: entryPath}
+			plan.Entries = append(plan.Entries, planned)
+
+			// Only builds into an existing directory can run into existing files
+			if opts.Into && doesPathExist(filepath.Join(path, entryPath)) {
+				planned.Conflict = opts.Conflicts
+			}
+
+			if entry.Template == nil || entry.Template.Path == "" {
+				continue
+			}
+
+			planned.Template = entry.Template.Path
+			if !filepath.IsAbs(planned.Template) {
+				planned.Template = filepath.Join(templatesDir, planned.Template)
+			}
+
+			// Render the template into the void; we're only interested in the keys it would prompt for
+			logger.Debugf("inspecting template %q", planned.Template)
+			data, err := os.ReadFile(planned.Template)
+			if err != nil {
+				logger.Debugf("failed to read template %q: %v", planned.Template, err)
+				planned.TemplateMissing = true
+				continue
+			}
+
+			if err = tmpl.Parse(ctx, io.Discard, data); err != nil {
+				return nil, errors.Wrapf(err, "inspect template %q", planned.Template)
+			}
+		}
+	}
+
+	if _package.Plugins != nil {
+		plan.Plugins = append(plan.Plugins, planPlugins("post", _package.Plugins.Post, pluginsDir)...)
+	}
+
+	plan.Variables = recorder.keys
+
+	return plan, nil
+}
+
+// conflictNote describes how an already existing file would be handled under the given policy.
+func conflictNote(policy builder.ConflictPolicy) string {
+	switch policy {
+	case "":
+		return ""
+	case builder.ConflictSkip:
+		return "exists; would be skipped"
+	case builder.ConflictOverwrite:
+		return "exists; would be overwritten"
+	case builder.ConflictBackup:
+		return "exists; would be backed up"
+	case builder.ConflictPrompt:
+		return "exists; would be prompted for"
+	default:
+		return "exists; the build would fail"
+	}
+}
+
+func printPlan(plan *projectPlan) error {
+	fmt.Printf("\nDry-run for project %q from package %q (%s)\n", plan.Path, plan.Package.Name, plan.Package.Label)
+	switch {
+	case plan.Into && !plan.PathExists:
+		fmt.Printf("Warning: path %q doesn't exist; scaffolding into it would fail\n", plan.Path)
+	case !plan.Into && plan.PathExists:
+		fmt.Printf("Warning: path %q already exists; creating the project would fail\n", plan.Path)
+	}
+
+	if len(plan.Entries) > 0 {
+		fmt.Println("\nEntries to create:")
+
+		table := text.NewTablePrinter()
+		table.AddHeaderColumns("#", "Type", "Path", "Template", "Conflict")
+		for idx, entry := range plan.Entries {
+			entryType := "file"
+			if entry.IsDir {
+				entryType = "dir"
+			}
+
+			template := entry.Template
+			if entry.TemplateMissing {
+				template += " (not found)"
+			}
+
+			table.AddRow(idx+1, entryType, entry.Path, template, conflictNote(entry.Conflict))
+		}
+
+		if err := table.Render(); err != nil {
+			return errors.Wrap(err, "render entries table")
+		}
+	}
+
+	if len(plan.Plugins) > 0 {
+		fmt.Println("\nPlugins to run:")
+
+		table := text.NewTablePrinter()
+		table.AddHeaderColumns("#", "Stage", "Path")
+		for idx, plugin := range plan.Plugins {
+			table.AddRow(idx+1, plugin.Stage, plugin.Path)
+		}
+
+		if err := table.Render(); err != nil {
+			return errors.Wrap(err, "render plugins table")
+		}
+	}
+
+	if len(plan.Variables) > 0 {
+		fmt.Println("\nVariables to prompt for:")
+		fmt.Println("   " + strings.Join(plan.Variables, "\n   "))
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+// planProject resolves the package and prints what creating a project from it would do. It neither touches the
+// filesystem nor the project database. The options are the ones that the build would get.
+func planProject(ctx context.Context, packageLabels []string, name string, opts *buildOptions) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get package manager from session
+	logger.Debug("getting package manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	pama := session.PackageManager
+	if pama == nil {
+		return errors.New("no package manager found")
+	}
+
+	// Get absolute path to project
+	name = strings.TrimSpace(name)
+	path, err := localPathToAbsPath(name)
+	if err != nil {
+		return errors.Wrapf(err, "get absolute path to project %q", name)
+	}
+	path = filepath.Clean(path)
+
+	// Try to load package by label; layered packages get composed into a single one
+	_package, err := loadPackage(ctx, pama, packageLabels...)
+	if err != nil {
+		return errors.Wrap(err, "load package")
+	}
+
+	logger.Debugf("planning project from package %q at path %q", _package.Label, path)
+	plan, err := buildPlan(ctx, _package, path, session.Config.TemplatesDir(), session.Config.PluginsDir(), opts)
+	if err != nil {
+		return errors.Wrapf(err, "plan project %q from %q", path, _package.Label)
+	}
+
+	return printPlan(plan)
+}
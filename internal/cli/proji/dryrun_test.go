@@ -0,0 +1,119 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+func TestBuildPlan(t *testing.T) {
+	t.Parallel()
+
+	_package := &domain.Package{
+		Label: "go",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "%{{project-name}}%", IsDir: true},
+			{Path: "docs/%{{broken}/README.md"}, // Not a valid template; used as is
+		}},
+	}
+
+	plan, err := buildPlan(context.Background(), _package, t.TempDir()+"/app", "", "", nil)
+	if err != nil {
+		t.Fatalf("buildPlan() error = %v", err)
+	}
+
+	want := []*plannedEntry{
+		{Path: "<Project Name>", IsDir: true},
+		{Path: "docs", IsDir: true},
+		{Path: "docs/%{{broken}", IsDir: true},
+		{Path: "docs/%{{broken}/README.md"},
+	}
+	if diff := cmp.Diff(want, plan.Entries); diff != "" {
+		t.Fatalf("buildPlan() entries mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Project Name"}, plan.Variables); diff != "" {
+		t.Fatalf("buildPlan() variables mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildPlan_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("old"), 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	_package := &domain.Package{
+		Label: "go",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "README.md"},
+			{Path: "Makefile"},
+		}},
+	}
+
+	cases := []struct {
+		name string
+		opts *buildOptions
+		want builder.ConflictPolicy
+	}{
+		{name: "new project", opts: &buildOptions{Conflicts: builder.ConflictBackup}, want: ""},
+		{name: "skip", opts: &buildOptions{Into: true, Conflicts: builder.ConflictSkip}, want: builder.ConflictSkip},
+		{name: "backup", opts: &buildOptions{Into: true, Conflicts: builder.ConflictBackup}, want: builder.ConflictBackup},
+		{name: "fail", opts: &buildOptions{Into: true, Conflicts: builder.ConflictFail}, want: builder.ConflictFail},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := buildPlan(context.Background(), _package, root, "", "", tc.opts)
+			if err != nil {
+				t.Fatalf("buildPlan() error = %v", err)
+			}
+
+			want := []*plannedEntry{{Path: "README.md", Conflict: tc.want}, {Path: "Makefile"}}
+			if diff := cmp.Diff(want, plan.Entries); diff != "" {
+				t.Fatalf("buildPlan() entries mismatch (-want +got):\n%s", diff)
+			}
+			if plan.Into != tc.opts.Into {
+				t.Fatalf("buildPlan() into = %v, want %v", plan.Into, tc.opts.Into)
+			}
+		})
+	}
+}
+
+func TestBuildPlan_Answers(t *testing.T) {
+	t.Parallel()
+
+	_package := &domain.Package{
+		Label: "go",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "%{{project-name}}%/%{{module}}%", IsDir: true},
+		}},
+	}
+
+	opts := &buildOptions{Answers: map[string]string{"project-name": "billing"}}
+	plan, err := buildPlan(context.Background(), _package, t.TempDir()+"/billing", "", "", opts)
+	if err != nil {
+		t.Fatalf("buildPlan() error = %v", err)
+	}
+
+	want := []*plannedEntry{
+		{Path: "billing", IsDir: true},
+		{Path: "billing/<Module>", IsDir: true},
+	}
+	if diff := cmp.Diff(want, plan.Entries); diff != "" {
+		t.Fatalf("buildPlan() entries mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Module"}, plan.Variables); diff != "" {
+		t.Fatalf("buildPlan() variables mismatch (-want +got):\n%s", diff)
+	}
+}
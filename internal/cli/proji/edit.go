@@ -0,0 +1,176 @@
+package proji
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+// editOptions holds the changes that should be applied to a project. Nil fields are left untouched.
+type editOptions struct {
+	Name        *string
+	Description *string
+	Tags        []string // Replaces all tags if set
+	AddTags     []string
+	RemoveTags  []string
+}
+
+func projectEditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "edit [OPTIONS] ID",
+		Short:                 "Change the name, description or tags of a project",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji project edit --name billing-api cf1l3q4bvs0e0m0ibmcg
+  proji project edit --description "Invoicing for client A" --add-tag client-a cf1l3q4bvs0e0m0ibmcg
+  proji project edit --description "" cf1l3q4bvs0e0m0ibmcg
+  proji project edit --tag archived --tag team-b cf1l3q4bvs0e0m0ibmcg`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := parseEditOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			return editProject(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().String("name", "", "New name of the project")
+	cmd.Flags().String("description", "", "New description of the project; empty to clear it")
+	cmd.Flags().StringSlice("tag", nil, "Replace all tags of the project; may be given multiple times")
+	cmd.Flags().StringSlice("add-tag", nil, "Add a tag to the project; may be given multiple times")
+	cmd.Flags().StringSlice("remove-tag", nil, "Remove a tag from the project; may be given multiple times")
+
+	cmd.MarkFlagsMutuallyExclusive("tag", "add-tag")
+	cmd.MarkFlagsMutuallyExclusive("tag", "remove-tag")
+
+	return cmd
+}
+
+// parseEditOptions reads the options of the edit command from its flags. Only flags that were given are set; an empty
+// --description is kept, as it clears the description.
+func parseEditOptions(cmd *cobra.Command) (*editOptions, error) {
+	flags := cmd.Flags()
+	opts := &editOptions{}
+
+	var err error
+	if opts.AddTags, err = flags.GetStringSlice("add-tag"); err != nil {
+		return nil, err
+	}
+	if opts.RemoveTags, err = flags.GetStringSlice("remove-tag"); err != nil {
+		return nil, err
+	}
+
+	if flags.Changed("name") {
+		name, err := flags.GetString("name")
+		if err != nil {
+			return nil, err
+		}
+		opts.Name = &name
+	}
+	if flags.Changed("description") {
+		description, err := flags.GetString("description")
+		if err != nil {
+			return nil, err
+		}
+		opts.Description = &description
+	}
+	if flags.Changed("tag") {
+		if opts.Tags, err = flags.GetStringSlice("tag"); err != nil {
+			return nil, err
+		}
+		if opts.Tags == nil {
+			opts.Tags = []string{} // An empty --tag removes all tags
+		}
+	}
+
+	return opts, nil
+}
+
+// editTags applies the tag changes of the options to the given tags. It returns nil if the tags are left untouched.
+func editTags(current []string, opts *editOptions) []string {
+	if opts.Tags != nil {
+		return domain.NormalizeTags(opts.Tags)
+	}
+	if len(opts.AddTags) == 0 && len(opts.RemoveTags) == 0 {
+		return nil
+	}
+
+	remove := make(map[string]struct{}, len(opts.RemoveTags))
+	for _, tag := range domain.NormalizeTags(opts.RemoveTags) {
+		remove[tag] = struct{}{}
+	}
+
+	tags := make([]string, 0, len(current)+len(opts.AddTags))
+	tags = append(tags, current...)
+	tags = append(tags, opts.AddTags...)
+
+	kept := make([]string, 0, len(tags))
+	for _, tag := range domain.NormalizeTags(tags) {
+		if _, exists := remove[tag]; !exists {
+			kept = append(kept, tag)
+		}
+	}
+
+	return kept
+}
+
+// editUpdate returns the update that applies the options to the project.
+func editUpdate(project *domain.Project, opts *editOptions) (*domain.ProjectUpdate, error) {
+	update := &domain.ProjectUpdate{
+		ID:          project.ID,
+		Description: opts.Description,
+		Tags:        editTags(project.Tags, opts),
+	}
+
+	if opts.Name != nil {
+		update.Name = strings.TrimSpace(*opts.Name)
+		if update.Name == "" {
+			return nil, errors.New("project name must not be empty")
+		}
+	}
+
+	if update.Name == "" && update.Description == nil && update.Tags == nil {
+		return nil, errors.New("nothing to change; use --name, --description, --tag, --add-tag or --remove-tag")
+	}
+
+	return update, nil
+}
+
+func editProject(ctx context.Context, projectID string, opts *editOptions) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	project, err := prma.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "get project %q", projectID)
+	}
+
+	update, err := editUpdate(&project, opts)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("updating project %q", project.ID)
+	if err = prma.Update(ctx, update); err != nil {
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+
+	logger.Infof("Successfully updated project %q", project.Name)
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package proji
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestParseEditOptions(t *testing.T) {
+	t.Parallel()
+
+	text := func(s string) *string { return &s }
+
+	cases := []struct {
+		name    string
+		args    []string
+		want    *editOptions
+		wantErr bool
+	}{
+		{
+			name: "no flags",
+			args: []string{},
+			want: &editOptions{AddTags: []string{}, RemoveTags: []string{}},
+		},
+		{
+			name: "name and description",
+			args: []string{"--name", "billing", "--description", "Invoicing"},
+			want: &editOptions{
+				Name: text("billing"), Description: text("Invoicing"), AddTags: []string{}, RemoveTags: []string{},
+			},
+		},
+		{
+			name: "empty description",
+			args: []string{"--description", ""},
+			want: &editOptions{Description: text(""), AddTags: []string{}, RemoveTags: []string{}},
+		},
+		{
+			name: "replace tags",
+			args: []string{"--tag", "a", "--tag", "b,c"},
+			want: &editOptions{Tags: []string{"a", "b", "c"}, AddTags: []string{}, RemoveTags: []string{}},
+		},
+		{
+			name: "add and remove tags",
+			args: []string{"--add-tag", "a", "--remove-tag", "b"},
+			want: &editOptions{AddTags: []string{"a"}, RemoveTags: []string{"b"}},
+		},
+		{
+			name:    "replace and add tags",
+			args:    []string{"--tag", "a", "--add-tag", "b"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := projectEditCommand()
+			err := cmd.ParseFlags(tc.args)
+			if err == nil {
+				err = cmd.ValidateFlagGroups()
+			}
+
+			var got *editOptions
+			if err == nil {
+				got, err = parseEditOptions(cmd)
+			}
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseEditOptions() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("parseEditOptions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	t.Parallel()
+
+	current := []string{"client-a", "team-b"}
+
+	cases := []struct {
+		name string
+		opts *editOptions
+		want []string
+	}{
+		{name: "untouched", opts: &editOptions{}, want: nil},
+		{name: "replace", opts: &editOptions{Tags: []string{"New", "new"}}, want: []string{"new"}},
+		{name: "clear", opts: &editOptions{Tags: []string{}}, want: []string{}},
+		{name: "add", opts: &editOptions{AddTags: []string{"Go"}}, want: []string{"client-a", "go", "team-b"}},
+		{name: "add existing", opts: &editOptions{AddTags: []string{"TEAM-B"}}, want: []string{"client-a", "team-b"}},
+		{name: "remove", opts: &editOptions{RemoveTags: []string{"Client-A"}}, want: []string{"team-b"}},
+		{name: "remove missing", opts: &editOptions{RemoveTags: []string{"x"}}, want: []string{"client-a", "team-b"}},
+		{
+			name: "add and remove",
+			opts: &editOptions{AddTags: []string{"go", "rust"}, RemoveTags: []string{"rust", "team-b"}},
+			want: []string{"client-a", "go"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.want, editTags(current, tc.opts)); diff != "" {
+				t.Fatalf("editTags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEditUpdate(t *testing.T) {
+	t.Parallel()
+
+	text := func(s string) *string { return &s }
+	project := &domain.Project{ID: "abc", Name: "app", Description: text("Old."), Tags: []string{"go"}}
+
+	cases := []struct {
+		name    string
+		opts    *editOptions
+		want    *domain.ProjectUpdate
+		wantErr bool
+	}{
+		{name: "nothing to change", opts: &editOptions{}, wantErr: true},
+		{name: "empty name", opts: &editOptions{Name: text("  ")}, wantErr: true},
+		{
+			name: "name is trimmed",
+			opts: &editOptions{Name: text(" billing ")},
+			want: &domain.ProjectUpdate{ID: "abc", Name: "billing"},
+		},
+		{
+			name: "empty description",
+			opts: &editOptions{Description: text("")},
+			want: &domain.ProjectUpdate{ID: "abc", Description: text("")},
+		},
+		{
+			name: "tags",
+			opts: &editOptions{AddTags: []string{"api"}},
+			want: &domain.ProjectUpdate{ID: "abc", Tags: []string{"api", "go"}},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := editUpdate(project, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("editUpdate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("editUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEditUpdate_ClearsDescription(t *testing.T) {
+	t.Parallel()
+
+	description := "Old."
+	project := &domain.Project{ID: "abc", Name: "app", Description: &description}
+
+	empty := ""
+	update, err := editUpdate(project, &editOptions{Description: &empty})
+	if err != nil {
+		t.Fatalf("editUpdate() error = %v", err)
+	}
+
+	project.ApplyUpdate(update)
+	if project.Description != nil {
+		t.Fatalf("expected description to be cleared, got %q", *project.Description)
+	}
+}
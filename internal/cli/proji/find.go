@@ -0,0 +1,72 @@
+package proji
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/projects"
+)
+
+func projectFindCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:                   "find [OPTIONS] QUERY",
+		Short:                 "Fuzzy search projects by name, path and description",
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji find billing
+  proji find client-a api
+  proji find --limit 3 gw`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return findProjects(cmd.Context(), strings.Join(args, " "), limit)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum number of results; 0 lists all")
+
+	return cmd
+}
+
+func findProjects(ctx context.Context, query string, limit int) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager available")
+	}
+
+	logger.Debug("fetching project list")
+	projectList, err := prma.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return errors.Wrap(err, "fetch projects")
+	}
+
+	logger.Debugf("searching %d projects for %q", len(projectList), query)
+	results := projects.Search(projectList, query)
+	if len(results) == 0 {
+		logger.Infof("No projects found for %q", query)
+		return nil
+	}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	found := make([]domain.Project, 0, len(results))
+	for _, result := range results {
+		found = append(found, *result.Project)
+	}
+
+	return errors.Wrap(renderProjects(found), "render table")
+}
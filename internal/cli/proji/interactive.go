@@ -0,0 +1,181 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+// promptInput prompts the user for a line of input. The trimmed answer is returned; an empty answer returns the
+// default value, which is shown in brackets if it's not empty.
+func promptInput(question, defaultValue string) (string, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+
+	if defaultValue != "" {
+		question += " [" + defaultValue + "]"
+	}
+
+	if _, err := fmt.Printf("   > %s: ", question); err != nil {
+		return "", errors.Wrapf(err, "prompt %q", question)
+	}
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil {
+		return "", errors.Wrapf(err, "read answer to %q", question)
+	}
+
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue, nil
+	}
+
+	return answer, nil
+}
+
+// pickPackage returns the package that the answer refers to, either by its position in the list or by its label.
+func pickPackage(packageList []domain.Package, answer string) (*domain.Package, bool) {
+	if num, err := strconv.Atoi(answer); err == nil {
+		if num < 1 || num > len(packageList) {
+			return nil, false
+		}
+
+		return &packageList[num-1], true
+	}
+
+	for idx := range packageList {
+		if strings.EqualFold(packageList[idx].Label, answer) {
+			return &packageList[idx], true
+		}
+	}
+
+	return nil, false
+}
+
+// choosePackage lists the installed packages and lets the user choose one of them by its number or label.
+func choosePackage(ctx context.Context) (*domain.Package, error) {
+	logger := simplog.FromContext(ctx)
+
+	// Get package manager from session
+	logger.Debug("getting package manager from cli session")
+	pama := cli.SessionFromContext(ctx).PackageManager
+	if pama == nil {
+		return nil, errors.New("no package manager found")
+	}
+
+	logger.Debug("fetching package list")
+	packageList, err := pama.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return nil, errors.Wrap(err, "fetch packages")
+	}
+	if len(packageList) == 0 {
+		return nil, errors.New("no packages installed; install one with 'proji package install' first")
+	}
+
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Label", "Name", "Description")
+	for idx, _package := range packageList {
+		description := ""
+		if _package.Description != nil {
+			description = *_package.Description
+		}
+
+		table.AddRow(idx+1, _package.Label, _package.Name, description)
+	}
+	if err = table.Render(); err != nil {
+		return nil, errors.Wrap(err, "render package list")
+	}
+	fmt.Println()
+
+	for {
+		answer, err := promptInput(fmt.Sprintf("Package (1-%d or label)", len(packageList)), "")
+		if err != nil {
+			return nil, err
+		}
+
+		if _package, ok := pickPackage(packageList, answer); ok {
+			return _package, nil
+		}
+
+		fmt.Printf("     %q is not one of the listed packages\n", answer)
+	}
+}
+
+// defaultProjectsRoot returns the directory that new projects are suggested to be created in. The root of the given
+// workspace wins over the projects root of the config; without either, the current working directory is used.
+func defaultProjectsRoot(ctx context.Context, workspace *domain.Workspace) string {
+	if workspace != nil && workspace.Root != "" {
+		return workspace.Root
+	}
+
+	if conf := cli.SessionFromContext(ctx).Config; conf != nil && conf.Projects.Root != "" {
+		return fsutil.ExpandPath(conf.Projects.Root)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	return cwd
+}
+
+// newProjectInteractive guides the user through the creation of a new project. The user picks one of the installed
+// packages and chooses the name and path of the project; the values of template variables are collected during the
+// build, as usual. If into is set, the package gets applied to that directory instead and only the package is asked
+// for.
+func newProjectInteractive(ctx context.Context, into string, workspace *domain.Workspace,
+	conflicts builder.ConflictPolicy,
+) error {
+	_package, err := choosePackage(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := &buildOptions{Into: into != "", Conflicts: conflicts, Summary: true}
+	if workspace != nil {
+		opts.Workspace = workspace.Name
+	}
+
+	if into != "" {
+		return newProject(ctx, _package.Label, into, opts)
+	}
+
+	for opts.Name == "" {
+		if opts.Name, err = promptInput("Project name", ""); err != nil {
+			return err
+		}
+	}
+
+	// The suggested path follows the destination pattern of the package, unless the project joins a workspace
+	suggestion := filepath.Join(defaultProjectsRoot(ctx, workspace), opts.Name)
+	if workspace == nil {
+		suggestion, opts.Answers, err = projectDestination(ctx, _package.Label, opts.Name, missingTemplateKeyFn)
+		if err != nil {
+			return err
+		}
+		if suggestion, err = localPathToAbsPath(suggestion); err != nil {
+			return errors.Wrapf(err, "get absolute path to project %q", opts.Name)
+		}
+	}
+
+	path, err := promptInput("Project path", suggestion)
+	if err != nil {
+		return err
+	}
+
+	return newProject(ctx, _package.Label, fsutil.ExpandPath(path), opts)
+}
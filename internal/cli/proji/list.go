@@ -3,6 +3,9 @@ package proji
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/nikoksr/simplog"
@@ -10,26 +13,155 @@ import (
 
 	"github.com/nikoksr/proji/internal/cli"
 	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/projects"
 )
 
+// listOptions controls which projects get listed and in which order.
+type listOptions struct {
+	Packages      []string
+	Name          string
+	NameRegex     string
+	Tags          []string
+	CreatedAfter  string
+	CreatedBefore string
+	Exists        bool
+	Missing       bool
+	Sort          string
+	Reverse       bool
+	Limit         int
+	Workspace     string
+}
+
 func projectListCommand() *cobra.Command {
+	opts := &listOptions{}
+
 	cmd := &cobra.Command{
-		Use:                   "ls",
+		Use:                   "ls [OPTIONS]",
 		Short:                 "List previously created projects",
 		Args:                  cobra.ExactArgs(0),
 		DisableFlagsInUseLine: true,
 
+		Example: `  proji ls
+  proji ls --tag client-a --tag wip
+  proji ls --package go --name api --sort name
+  proji ls --created-after 2023-01-01 --missing
+  proji ls --sort updated --reverse --limit 10
+  proji ls --workspace shop`,
+
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listProjects(cmd.Context())
+			return listProjects(cmd.Context(), opts)
 		},
 	}
 
+	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", nil,
+		"Only list projects of this package; may be given multiple times")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Only list projects whose name contains this text")
+	cmd.Flags().StringVar(&opts.NameRegex, "name-regex", "", "Only list projects whose name matches this regex")
+	cmd.Flags().StringSliceVarP(&opts.Tags, "tag", "t", nil,
+		"Only list projects with this tag; may be given multiple times to require all of them")
+	cmd.Flags().StringVar(&opts.CreatedAfter, "created-after", "",
+		"Only list projects created at or after this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&opts.CreatedBefore, "created-before", "",
+		"Only list projects created before this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().BoolVar(&opts.Exists, "exists", false, "Only list projects whose path exists")
+	cmd.Flags().BoolVar(&opts.Missing, "missing", false, "Only list projects whose path does not exist")
+	cmd.Flags().StringVarP(&opts.Sort, "sort", "s", string(projects.SortByCreated),
+		"Sort by id, name, package, path, created or updated")
+	cmd.Flags().BoolVarP(&opts.Reverse, "reverse", "r", false, "Reverse the sort order")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "n", 0, "Maximum number of projects to list; 0 lists all")
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Only list projects of this workspace")
+
+	cmd.MarkFlagsMutuallyExclusive("exists", "missing")
+
 	return cmd
 }
 
-func listProjects(ctx context.Context) error {
+// parseDate parses a date given either as YYYY-MM-DD in local time or in RFC 3339 format.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Newf("invalid date %q; use YYYY-MM-DD or RFC 3339", value)
+	}
+
+	return date, nil
+}
+
+// filter converts the options into a project filter.
+func (opts *listOptions) filter() (*projects.Filter, error) {
+	filter := &projects.Filter{
+		Packages: opts.Packages,
+		Name:     opts.Name,
+		Tags:     opts.Tags,
+	}
+
+	var err error
+	if opts.NameRegex != "" {
+		if filter.NameRegex, err = regexp.Compile(opts.NameRegex); err != nil {
+			return nil, errors.Wrapf(err, "compile name regex %q", opts.NameRegex)
+		}
+	}
+	if opts.CreatedAfter != "" {
+		if filter.CreatedAfter, err = parseDate(opts.CreatedAfter); err != nil {
+			return nil, err
+		}
+	}
+	if opts.CreatedBefore != "" {
+		if filter.CreatedBefore, err = parseDate(opts.CreatedBefore); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Exists || opts.Missing {
+		exists := opts.Exists
+		filter.Exists = &exists
+	}
+
+	return filter, nil
+}
+
+// renderProjects prints the projects as a table.
+func renderProjects(projectList []domain.Project) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "ID", "Name", "Package", "Path", "Tags", "Created at")
+
+	for idx, project := range projectList {
+		table.AddRow(idx+1, project.ID, project.Name, project.Package, project.Path, strings.Join(project.Tags, ", "),
+			project.CreatedAt)
+	}
+
+	return table.Render()
+}
+
+func listProjects(ctx context.Context, opts *listOptions) error {
 	logger := simplog.FromContext(ctx)
 
+	filter, err := opts.filter()
+	if err != nil {
+		return err
+	}
+
+	sortField, err := projects.ParseSortField(opts.Sort)
+	if err != nil {
+		return err
+	}
+
+	if opts.Workspace != "" {
+		workspace, err := getWorkspace(ctx, opts.Workspace)
+		if err != nil {
+			return err
+		}
+
+		filter.IDs = workspace.Projects
+		if filter.IDs == nil {
+			filter.IDs = []string{}
+		}
+	}
+
 	// Get project manager from session
 	logger.Debug("getting project manager from cli session")
 	prma := cli.SessionFromContext(ctx).ProjectManager
@@ -39,28 +171,26 @@ func listProjects(ctx context.Context) error {
 
 	// Call the projects.
 	logger.Debug("fetching project list")
-	projects, err := prma.Fetch(ctx)
+	projectList, err := prma.Fetch(ctx)
 	if err != nil {
 		return errors.Wrap(err, "fetch projects")
 	}
 
+	projectList = projects.FilterProjects(projectList, filter)
+	projects.SortProjects(projectList, sortField, opts.Reverse)
+	if opts.Limit > 0 && len(projectList) > opts.Limit {
+		projectList = projectList[:opts.Limit]
+	}
+
 	// Exit when no projects are installed.
-	if len(projects) == 0 {
+	if len(projectList) == 0 {
 		return nil
 	}
 
 	// List all projects in a pretty table.
 	logger.Debug("listing projects")
 
-	fmt.Println()
-	table := text.NewTablePrinter()
-	table.AddHeaderColumns("#", "ID", "Name", "Package", "Path", "Created at")
-
-	for idx, project := range projects {
-		table.AddRow(idx+1, project.ID, project.Name, project.Package, project.Path, project.CreatedAt)
-	}
-
-	err = table.Render()
+	err = renderProjects(projectList)
 	if err != nil {
 		return errors.Wrap(err, "render table")
 	}
@@ -0,0 +1,187 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/fsutil"
+)
+
+func projectMoveCommand() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:                   "mv [OPTIONS] ID NEWPATH",
+		Aliases:               []string{"move"},
+		Short:                 "Move a project to a new location",
+		Args:                  cobra.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+
+		Long: `Moves the project directory on disk and updates the stored path of the project. If NEWPATH is an existing
+directory, the project is moved into it. Projects that are nested inside of the moved directory get their paths
+updated as well. If anything fails, the directory is moved back and all changes get reverted.`,
+
+		Example: `  proji project mv cf1l3q4bvs0e0m0ibmcg ~/code/clients/billing-api
+  proji project mv --name billing-api cf1l3q4bvs0e0m0ibmcg ~/code/clients/`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return moveProject(cmd.Context(), args[0], args[1], name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Rename the project as well")
+
+	return cmd
+}
+
+// moveTarget returns the absolute path that the directory at src should be moved to. Like mv, an existing directory
+// as dst means that src gets moved into it.
+func moveTarget(src, dst string) (string, error) {
+	dst, err := localPathToAbsPath(dst)
+	if err != nil {
+		return "", err
+	}
+	dst = filepath.Clean(dst)
+
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		dst = filepath.Join(dst, filepath.Base(src))
+	}
+
+	if dst == src {
+		return "", errors.Newf("project is already located at %q", src)
+	}
+	if strings.HasPrefix(dst, src+string(filepath.Separator)) {
+		return "", errors.Newf("can't move %q into itself", src)
+	}
+	if doesPathExist(dst) {
+		return "", errors.Newf("destination %q already exists", dst)
+	}
+
+	return dst, nil
+}
+
+// nestedProjects returns the projects whose path lies below dir.
+func nestedProjects(projectList []domain.Project, dir string) []*domain.Project {
+	nested := make([]*domain.Project, 0)
+	for idx := range projectList {
+		if strings.HasPrefix(projectList[idx].Path, dir+string(filepath.Separator)) {
+			nested = append(nested, &projectList[idx])
+		}
+	}
+
+	return nested
+}
+
+func moveProject(ctx context.Context, projectID, newPath, newName string) (err error) {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	project, err := prma.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "get project %q", projectID)
+	}
+	if project.Archive != nil && fsutil.IsDir(project.Path) {
+		return errors.Newf("project %q is archived but its directory was kept; reactivate it with 'proji project "+
+			"restore %s' first", project.Name, project.ID)
+	}
+	if project.Archive != nil {
+		return errors.Newf("project %q is archived; restore it to the new location with 'proji project restore %s %s'",
+			project.Name, project.ID, newPath)
+	}
+	if !doesPathExist(project.Path) {
+		return errors.Newf("path %q of project %q does not exist; use 'proji project relocate' to find moved projects",
+			project.Path, project.Name)
+	}
+
+	src := filepath.Clean(project.Path)
+	dst, err := moveTarget(src, newPath)
+	if err != nil {
+		return err
+	}
+
+	newName = strings.TrimSpace(newName)
+
+	// Nested projects move along with the directory. A failed fetch only means that their paths can't be fixed.
+	projectList, fetchErr := prma.Fetch(ctx)
+	if fetchErr != nil {
+		logger.Debugf("failed to fetch projects, nested projects won't be updated: %v", fetchErr)
+	}
+
+	// If the directory was copied to another device but the original couldn't be removed, the copy is complete while
+	// the original might not be anymore. The move carries on with the copy; the remains are left to the user.
+	logger.Debugf("moving %q to %q", src, dst)
+	srcLeft := false
+	if err = fsutil.Move(src, dst); err != nil {
+		if !errors.Is(err, fsutil.ErrSourceNotRemoved) {
+			return errors.Wrapf(err, "move project %q", project.Name)
+		}
+
+		logger.Warnf("Copied project %q to %q, but failed to remove %q; remove it manually: %v", project.Name, dst,
+			src, err)
+		srcLeft, err = true, nil
+	}
+
+	// Every applied update gets its inverse recorded, so that a failure can revert everything in reverse order
+	var reverts []*domain.ProjectUpdate
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		// The original directory is incomplete; the copy is the only complete version of the project and stays.
+		if srcLeft {
+			logger.Errorf("Failed to update project %q; its directory is located at %q now, remains of it are left "+
+				"at %q", project.Name, dst, src)
+			return
+		}
+
+		logger.Warnf("Moving project %q failed, rolling back", project.Name)
+		for idx := len(reverts) - 1; idx >= 0; idx-- {
+			if revertErr := prma.Update(ctx, reverts[idx]); revertErr != nil {
+				logger.Errorf("Failed to restore path of project %q: %v", reverts[idx].ID, revertErr)
+			}
+		}
+		if moveErr := fsutil.Move(dst, src); moveErr != nil {
+			logger.Errorf("Failed to move %q back to %q: %v", dst, src, moveErr)
+		}
+	}()
+
+	update := &domain.ProjectUpdate{ID: project.ID, Path: dst, Name: newName}
+	logger.Debugf("updating path of project %q to %q", project.ID, dst)
+	if err = prma.Update(ctx, update); err != nil {
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+	reverts = append(reverts, &domain.ProjectUpdate{ID: project.ID, Path: src, Name: project.Name})
+
+	for _, nested := range nestedProjects(projectList, src) {
+		nestedPath := filepath.Join(dst, strings.TrimPrefix(nested.Path, src))
+
+		logger.Debugf("updating path of nested project %q to %q", nested.ID, nestedPath)
+		if err = prma.Update(ctx, &domain.ProjectUpdate{ID: nested.ID, Path: nestedPath}); err != nil {
+			return errors.Wrapf(err, "update nested project %q", nested.ID)
+		}
+		reverts = append(reverts, &domain.ProjectUpdate{ID: nested.ID, Path: nested.Path})
+	}
+
+	if newName != "" {
+		logger.Infof("Successfully moved project %q to %q and renamed it to %q", project.Name, dst, newName)
+	} else {
+		logger.Infof("Successfully moved project %q to %q", project.Name, dst)
+	}
+
+	return nil
+}
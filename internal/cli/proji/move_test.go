@@ -0,0 +1,99 @@
+package proji
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestMoveTarget(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, dir := range []string{"a/b/sub", "a/bc", "clients", "taken/b"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("failed to create directory %q: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "file"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	src := filepath.Join(root, "a", "b")
+
+	cases := []struct {
+		name    string
+		dst     string
+		want    string
+		wantErr bool
+	}{
+		{name: "new path", dst: filepath.Join(root, "renamed"), want: filepath.Join(root, "renamed")},
+		{name: "into existing directory", dst: filepath.Join(root, "clients"), want: filepath.Join(root, "clients", "b")},
+		{name: "unclean path", dst: filepath.Join(root, "clients") + "/./", want: filepath.Join(root, "clients", "b")},
+		{name: "prefix sibling", dst: filepath.Join(root, "a", "bc", "x"), want: filepath.Join(root, "a", "bc", "x")},
+		{name: "same path", dst: src, wantErr: true},
+		{name: "parent directory", dst: filepath.Join(root, "a"), wantErr: true},
+		{name: "into itself", dst: filepath.Join(src, "sub"), wantErr: true},
+		{name: "below itself", dst: filepath.Join(src, "new"), wantErr: true},
+		{name: "existing file", dst: filepath.Join(root, "file"), wantErr: true},
+		{name: "existing entry in directory", dst: filepath.Join(root, "taken"), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := moveTarget(src, tc.dst)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("moveTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("moveTarget() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNestedProjects(t *testing.T) {
+	t.Parallel()
+
+	projectList := []domain.Project{
+		{ID: "1", Path: "/a/b"},
+		{ID: "2", Path: "/a/b/c"},
+		{ID: "3", Path: "/a/bc"},
+		{ID: "4", Path: "/a/b/c/d"},
+		{ID: "5", Path: "/a"},
+	}
+
+	cases := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{name: "nested", dir: "/a/b", want: []string{"2", "4"}},
+		{name: "deeply nested", dir: "/a/b/c", want: []string{"4"}},
+		{name: "no nested projects", dir: "/a/bc", want: []string{}},
+		{name: "all below root", dir: "/a", want: []string{"1", "2", "3", "4"}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]string, 0)
+			for _, project := range nestedProjects(projectList, tc.dir) {
+				got = append(got, project.ID)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("nestedProjects() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
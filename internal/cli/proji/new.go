@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/nikoksr/simplog"
@@ -16,141 +18,325 @@ import (
 
 	"github.com/nikoksr/proji/internal/cli"
 	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/packages"
 	"github.com/nikoksr/proji/pkg/plugins"
+	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/projects/builder"
 	"github.com/nikoksr/proji/pkg/templates"
 )
 
 // projectNewCommand returns a new instance of the new command.
 func projectNewCommand() *cobra.Command {
+	var dryRun bool
+	var into, conflicts, batch, workspaceName string
+	var parallel int
+
 	cmd := &cobra.Command{
-		Use:     "new",
-		Short:   "Create a new project",
-		Aliases: []string{"do", "create"},
-		Args:    cobra.ExactArgs(2),
+		Use:                   "new [OPTIONS] [LABEL[,LABEL...] PATH [PATH...]]",
+		Short:                 "Create a new project",
+		Aliases:               []string{"do", "create"},
+		Args:                  cobra.ArbitraryArgs,
+		DisableFlagsInUseLine: true,
+
+		Long: `Creates a new project from one or more packages. Without any arguments, proji lists the installed packages
+to choose from and asks for the name and path of the project; the path defaults to a directory below the workspace
+root, the projects root of the config (projects.root) or the current working directory.
+
+A PATH that is a plain name, e.g. 'my-project' rather than './my-project', is placed at the destination of the
+package. Destinations are templates like '~/code/go/%{{project-name}}%'; without one, the project is created below
+the projects root of the config, or in the current working directory if that's not set either. Both support a
+leading ~ and environment variables.
+
+Once the project was created, proji prints a tree of it, followed by the notes of the package, if it has any.`,
+
+		Example: `  proji new
+  proji new go my-project
+  proji new go-service,docker,github-actions my-service
+  proji new go service-a service-b service-c
+  proji new --batch projects.toml --parallel 4
+  proji new --dry-run go my-project
+  proji new --into . go
+  proji new --into ./my-project --conflicts backup go
+  proji new --workspace shop go cart-service`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			packageLabel := args[0]
-			path := args[1]
+			// Conflicts can only occur when scaffolding into an existing directory
+			if cmd.Flags().Changed("conflicts") && into == "" {
+				return errors.New("--conflicts can only be used together with --into")
+			}
+
+			// Batch mode; all projects are described by the batch file.
+			if batch != "" {
+				if len(args) > 0 || into != "" {
+					return errors.New("--batch can't be combined with arguments or --into")
+				}
+
+				specs, err := loadBatchFile(batch)
+				if err != nil {
+					return errors.Wrapf(err, "load batch file %q", batch)
+				}
+				for _, spec := range specs {
+					if spec.Workspace == "" {
+						spec.Workspace = workspaceName
+					}
+					if spec.Workspace != "" {
+						if _, err = getWorkspace(cmd.Context(), spec.Workspace); err != nil {
+							return err
+						}
+					}
+				}
+				if dryRun {
+					return planProjects(cmd.Context(), specs)
+				}
+
+				return createProjects(cmd.Context(), specs, parallel)
+			}
+
+			// Relative project paths are resolved against the root of the workspace
+			var workspace *domain.Workspace
+			if workspaceName != "" {
+				var err error
+				if workspace, err = getWorkspace(cmd.Context(), workspaceName); err != nil {
+					return err
+				}
+			}
 
-			return newProject(cmd.Context(), packageLabel, path)
+			// Without arguments, the user gets guided through the creation of the project.
+			if len(args) == 0 {
+				if dryRun {
+					return errors.New("--dry-run needs a package label and a path")
+				}
+
+				policy, err := builder.ParseConflictPolicy(conflicts)
+				if err != nil {
+					return err
+				}
+
+				return newProjectInteractive(cmd.Context(), into, workspace, policy)
+			}
+
+			packageLabels := parsePackageLabels(args[0])
+			if len(packageLabels) == 0 {
+				return errors.New("missing package label")
+			}
+
+			// Plain names are placed at the package's destination or below the projects root; during a dry-run,
+			// unknown template variables are only recorded.
+			missingKeyFn := missingTemplateKeyFn
+			if dryRun {
+				missingKeyFn = newKeyRecorder().missingKeyFn
+			}
+
+			// Several paths; create one project per path.
+			if into == "" && len(args) > 2 {
+				paths := args[1:]
+				names := make([]string, len(paths))
+				values := make([]map[string]string, len(paths))
+				for idx := range paths {
+					name := strings.TrimSpace(paths[idx])
+					switch {
+					case workspace != nil:
+						names[idx], paths[idx] = filepath.Base(filepath.Clean(name)), workspaceProjectPath(workspace, name)
+					case !isPathLike(name):
+						path, answers, err := projectDestination(cmd.Context(), packageLabels[0], name, missingKeyFn)
+						if err != nil {
+							return err
+						}
+						names[idx], paths[idx], values[idx] = name, path, answers
+					}
+				}
+
+				specs, err := specsFromPaths(args[0], paths)
+				if err != nil {
+					return err
+				}
+				for idx, spec := range specs {
+					spec.Name = names[idx]
+					spec.Values = values[idx]
+					spec.Workspace = workspaceName
+				}
+				if dryRun {
+					return planProjects(cmd.Context(), specs)
+				}
+
+				return createProjects(cmd.Context(), specs, parallel)
+			}
+
+			// When scaffolding into an existing directory, the path is given by the flag.
+			var path, name string
+			var answers map[string]string
+			switch {
+			case into != "" && len(args) == 1:
+				path = into
+			case into == "" && len(args) == 2:
+				path = args[1]
+				if workspace != nil {
+					path = workspaceProjectPath(workspace, strings.TrimSpace(path))
+					name = filepath.Base(filepath.Clean(path))
+				} else if !isPathLike(strings.TrimSpace(path)) {
+					name = strings.TrimSpace(path)
+					var err error
+					if path, answers, err = projectDestination(cmd.Context(), packageLabels[0], name, missingKeyFn); err != nil {
+						return err
+					}
+				}
+			case into != "":
+				return errors.New("path is given by --into; expected only a package label")
+			default:
+				return errors.New("missing project path")
+			}
+
+			policy, err := builder.ParseConflictPolicy(conflicts)
+			if err != nil {
+				return err
+			}
+
+			opts := &buildOptions{
+				Name:      name,
+				Into:      into != "",
+				Conflicts: policy,
+				Layers:    packageLabels[1:],
+				Answers:   answers,
+				Workspace: workspaceName,
+				Summary:   true,
+			}
+			if dryRun {
+				return planProject(cmd.Context(), packageLabels, path, opts)
+			}
+
+			return newProject(cmd.Context(), packageLabels[0], path, opts)
 		},
 	}
 
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created without touching the filesystem")
+	cmd.Flags().StringVar(&into, "into", "", "Scaffold into an existing directory instead of creating a new one")
+	cmd.Flags().StringVar(&conflicts, "conflicts", string(builder.ConflictFail),
+		"How to handle files that already exist when using --into (skip, overwrite, backup, prompt, fail)")
+	cmd.Flags().StringVar(&batch, "batch", "", "Create the projects listed in a TOML or JSON batch file")
+	cmd.Flags().IntVarP(&parallel, "parallel", "j", 1,
+		"Number of projects to create at a time; packages that run plugins are always created one at a time")
+	cmd.Flags().StringVarP(&workspaceName, "workspace", "w", "",
+		"Add the project to this workspace; relative paths are resolved against the workspace root")
+
 	return cmd
 }
 
-func localPathToAbsPath(path string) (string, error) {
-	if filepath.IsAbs(path) {
-		return path, nil
+// parsePackageLabels splits a comma separated list of package labels. Empty labels are dropped.
+func parsePackageLabels(arg string) []string {
+	var labels []string
+	for _, label := range strings.Split(arg, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
 	}
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", errors.Wrap(err, "get current working directory")
+	return labels
+}
+
+// loadPackages loads the packages with the given labels, in order.
+func loadPackages(ctx context.Context, pama packages.Manager, labels ...string) ([]*domain.Package, error) {
+	logger := simplog.FromContext(ctx)
+
+	layers := make([]*domain.Package, 0, len(labels))
+	for _, label := range labels {
+		logger.Debugf("loading package %s", label)
+		_package, err := pama.GetByLabel(ctx, label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get package %q", label)
+		}
+
+		layers = append(layers, &_package)
 	}
 
-	return filepath.Join(cwd, path), nil
+	return layers, nil
 }
 
-var missingTemplateKeyFn templates.MissingKeyFn = func(key string) (value string, err error) {
-	_, err = fmt.Printf("   > %s: ", cases.Title(language.Und, cases.NoLower).String(key))
+// loadPackage loads the packages with the given labels and composes them, in order, into a single package. See
+// packages.Compose for the merge rules.
+func loadPackage(ctx context.Context, pama packages.Manager, labels ...string) (*domain.Package, error) {
+	layers, err := loadPackages(ctx, pama, labels...)
 	if err != nil {
-		return "", errors.Wrapf(err, "prompt input for template key %q", key)
+		return nil, err
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-	value, err = reader.ReadString('\n')
-	if err != nil {
-		return "", errors.Wrapf(err, "read input for template key %q", key)
-	}
+	return packages.Compose(layers...)
+}
 
-	// Trim newline; note: strings.TrimSuffix checks if the string ends with the suffix before trimming
-	value = strings.TrimSuffix(value, "\n")
+// newManifest returns an empty manifest for a build of the given packages.
+func newManifest(layers []*domain.Package) *domain.ProjectManifest {
+	manifest := &domain.ProjectManifest{BuiltAt: time.Now()}
+	for _, layer := range layers {
+		manifest.Packages = append(manifest.Packages, &domain.ManifestPackage{
+			Label:    layer.Label,
+			Revision: layer.Revision,
+			SHA:      layer.SHA,
+		})
+	}
 
-	return value, nil
+	return manifest
 }
 
-func createEntry(ctx context.Context, entry *domain.DirEntry, templatesDir string, tmpl *templates.TemplateEngine) error {
-	logger := simplog.FromContext(ctx)
-
-	// Check if template path is a template string
-	entryPath := entry.Path
-	if parsedPath, err := tmpl.ParseToString(ctx, entryPath); err != nil {
-		logger.Debugf("template path %q is not a template string", entryPath)
-	} else {
-		entryPath = parsedPath
+func localPathToAbsPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
 	}
 
-	// If we have a file, get its directory and create it. This allows for implicit directory creation and may
-	// simplify the directory tree structure in a packages config vastly.
-	dirPath := entryPath
-	filePath := ""
-	if !entry.IsDir {
-		dirPath = filepath.Dir(entryPath)
-		filePath = entryPath
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", errors.Wrap(err, "get current working directory")
 	}
 
-	if dirPath != "." {
-		logger.Debugf("creating directory %q", dirPath)
-		if err := os.MkdirAll(dirPath, 0o755); err != nil {
-			return errors.Wrapf(err, "create directory %q", dirPath)
-		}
-	}
+	return filepath.Join(cwd, path), nil
+}
 
-	// Skip if we don't have a file path
-	if filePath == "" {
-		return nil
-	}
+// stdin is shared by all prompts. Creating a new buffered reader per prompt would swallow input that was already
+// buffered by a previous reader, e.g. when answers are piped into proji. Prompts are guarded by promptMu, so that
+// concurrent builds don't interleave their questions.
+var (
+	stdin    = bufio.NewReader(os.Stdin)
+	promptMu sync.Mutex
+)
 
-	logger.Debugf("creating file %q", filePath)
-	file, err := os.Create(filePath)
-	if err != nil {
-		if os.IsExist(err) {
-			// If the file already exists, we can ignore the error.
-			logger.Warnf("file %q already exists; skipping", filePath)
-		} else {
-			return errors.Wrapf(err, "create file %q", filePath)
-		}
+// newTemplateKeyPrompt returns a templates.MissingKeyFn that prompts the user for the value of a template key. If a
+// prefix is given, it is shown in front of the key; this helps to tell apart prompts of concurrent builds.
+func newTemplateKeyPrompt(prefix string) templates.MissingKeyFn {
+	if prefix != "" {
+		prefix = "[" + prefix + "] "
 	}
-	defer func() {
-		if ferr := file.Close(); ferr != nil {
-			logger.Errorf("error closing file %q: %v", filePath, ferr)
-		}
-	}()
 
-	// Skip if we have no template
-	if entry.Template == nil {
-		return nil
-	}
+	return func(key string) (value string, err error) {
+		promptMu.Lock()
+		defer promptMu.Unlock()
 
-	tmplPath := entry.Template.Path
-	if tmplPath == "" {
-		logger.Warnf("template %q has no path; skipping", entry.Template.ID)
-		return nil
-	}
+		_, err = fmt.Printf("   > %s%s: ", prefix, cases.Title(language.Und, cases.NoLower).String(key))
+		if err != nil {
+			return "", errors.Wrapf(err, "prompt input for template key %q", key)
+		}
 
-	// Check if template path is absolute
-	if !filepath.IsAbs(tmplPath) {
-		tmplPath = filepath.Join(templatesDir, tmplPath)
-	}
+		value, err = stdin.ReadString('\n')
+		if err != nil {
+			return "", errors.Wrapf(err, "read input for template key %q", key)
+		}
 
-	// Parse template
-	logger.Debugf("generating file %q from template %q", entryPath, entry.Template.ID)
-	logger.Debugf("parsing template from file %q", tmplPath)
-	if err = tmpl.ParseFile(ctx, file, tmplPath); err != nil {
-		return errors.Wrapf(err, "parse template from file %q", tmplPath)
-	}
+		// Trim newline; note: strings.TrimSuffix checks if the string ends with the suffix before trimming
+		value = strings.TrimSuffix(value, "\n")
 
-	return nil
+		return value, nil
+	}
 }
 
-func runPlugin(ctx context.Context, plugin *domain.Plugin, pluginsDir string) error {
+var missingTemplateKeyFn = newTemplateKeyPrompt("")
+
+// runPlugin runs the given plugin with dir as its working directory. It returns the resolved path of the plugin or an
+// empty string if the plugin was skipped.
+func runPlugin(ctx context.Context, plugin *domain.Plugin, pluginsDir, dir string) (string, error) {
 	logger := simplog.FromContext(ctx)
 
 	path := plugin.Path
 	if path == "" {
 		logger.Warnf("plugin %q has no path; skipping", plugin.ID)
-		return nil
+		return "", nil
 	}
 
 	if !filepath.IsAbs(path) {
@@ -159,10 +345,61 @@ func runPlugin(ctx context.Context, plugin *domain.Plugin, pluginsDir string) er
 
 	logger.Infof("Running plugin %q", filepath.Base(path))
 
-	return plugins.Run(ctx, path)
+	return path, plugins.RunIn(ctx, path, dir)
+}
+
+// runPlugins runs the given plugins, in order, inside of the project directory dir. Plugins that were run get recorded
+// in the manifest.
+func runPlugins(
+	ctx context.Context, stage string, plugins []*domain.Plugin, pluginsDir, dir string, manifest *domain.ProjectManifest,
+) error {
+	for _, plugin := range plugins {
+		path, err := runPlugin(ctx, plugin, pluginsDir, dir)
+		if err != nil {
+			return errors.Wrapf(err, "run %s-run plugin %q", stage, plugin.ID)
+		}
+
+		if path != "" {
+			manifest.Plugins = append(manifest.Plugins, &domain.ManifestPlugin{Stage: stage, Path: path})
+		}
+	}
+
+	return nil
 }
 
-func buildProject(ctx context.Context, project *domain.ProjectAdd) error {
+// buildOptions control how buildProject creates a project.
+type buildOptions struct {
+	// Name overrides the name of the project that gets stored; defaults to the given path.
+	Name string
+
+	// Into indicates that the project gets scaffolded into an already existing directory.
+	Into bool
+
+	// Conflicts is the policy that is used for files that already exist. It's only relevant when Into is set.
+	Conflicts builder.ConflictPolicy
+
+	// Layers are the labels of additional packages that get layered on top of the project's package. See
+	// packages.Compose for the merge rules.
+	Layers []string
+
+	// MissingKeyFn is called for template variables that have no value yet. Defaults to prompting the user.
+	MissingKeyFn templates.MissingKeyFn
+
+	// Answers pre-fill the values of template variables; the user only gets prompted for variables that are missing.
+	// Values that get collected during the build are added to the map, so that the caller can persist them.
+	Answers map[string]string
+
+	// Workspace is the name of a workspace that the project gets added to once it's stored.
+	Workspace string
+
+	// Summary prints a tree of the created project and the notes of its package once the project was created.
+	Summary bool
+
+	// Notes is set to the rendered notes of the package by the build if Summary is set.
+	Notes string
+}
+
+func buildProject(ctx context.Context, project *domain.ProjectAdd, opts *buildOptions) (report *builder.Report, err error) {
 	logger := simplog.FromContext(ctx)
 
 	// Get package manager from session
@@ -177,87 +414,187 @@ func buildProject(ctx context.Context, project *domain.ProjectAdd) error {
 	// Get package manager from session
 	pama := session.PackageManager
 	if pama == nil {
-		return errors.New("no package manager found")
+		return nil, errors.New("no package manager found")
 	}
 
-	// Try to load package by label
-	logger.Debugf("loading package %s", project.Package)
-	_package, err := pama.GetByLabel(ctx, project.Package)
-	if err != nil {
-		return errors.Wrapf(err, "get package %q", project.Package)
+	if opts == nil {
+		opts = &buildOptions{Conflicts: builder.ConflictFail}
 	}
 
-	// Create project from package at path
-	logger.Debugf("creating project from package %q at path %q", _package.Label, project.Path)
-
-	// Create base directory
-	logger.Infof("Creating base directory %q", project.Path)
-	if err = os.Mkdir(project.Path, 0o755); err != nil {
-		if os.IsExist(err) {
-			return errors.Newf("path %q already exists", project.Path)
-		}
-
-		return errors.Wrapf(err, "create project at path %q", project.Path)
+	// Try to load package by label; layered packages get composed into a single one
+	layers, err := loadPackages(ctx, pama, append([]string{project.Package}, opts.Layers...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "load package")
 	}
 
-	// Get current working directory
-	logger.Debugf("getting current working directory")
-	cwd, err := os.Getwd()
+	_package, err := packages.Compose(layers...)
 	if err != nil {
-		return errors.Wrap(err, "get current working directory")
+		return nil, errors.Wrap(err, "compose packages")
 	}
-	logger.Debugf("current working directory: %s", cwd)
 
-	// Change to base path
-	logger.Debugf("changing to project base path %q", project.Path)
-	if err = os.Chdir(project.Path); err != nil {
-		return errors.Wrapf(err, "change to base path %q", project.Path)
+	// The manifest records what the build produces
+	manifest := newManifest(layers)
+
+	// Create project from package at path
+	logger.Debugf("creating project from package %q at path %q", _package.Label, project.Path)
+
+	report = &builder.Report{}
+	if opts.Answers == nil {
+		opts.Answers = make(map[string]string)
 	}
 
-	// Make sure to change back to original directory
-	defer func() {
-		logger.Debugf("changing back to original directory %q", cwd)
-		if ferr := os.Chdir(cwd); ferr != nil {
-			err = errors.CombineErrors(err, ferr)
+	if opts.Into {
+		// Scaffolding into an existing directory; make sure that it is one.
+		logger.Infof("Scaffolding into existing directory %q", project.Path)
+		info, err := os.Stat(project.Path)
+		if err != nil {
+			return report, errors.Wrapf(err, "stat project path %q", project.Path)
+		}
+		if !info.IsDir() {
+			return report, errors.Newf("path %q is not a directory", project.Path)
+		}
+	} else {
+		// Create base directory; its parents might not exist yet if the path stems from a package's destination
+		logger.Infof("Creating base directory %q", project.Path)
+		if err = os.MkdirAll(filepath.Dir(project.Path), 0o755); err != nil {
+			return report, errors.Wrapf(err, "create parent directory of %q", project.Path)
+		}
+		if err = os.Mkdir(project.Path, 0o755); err != nil {
+			if os.IsExist(err) {
+				return report, errors.Newf("path %q already exists", project.Path)
+			}
+
+			return report, errors.Wrapf(err, "create project at path %q", project.Path)
 		}
-	}()
+	}
 
 	// Pre-run plugins
 	if _package.Plugins != nil {
-		for _, plugin := range _package.Plugins.Pre {
-			if err = runPlugin(ctx, plugin, pluginsDir); err != nil {
-				return errors.Wrapf(err, "run pre-run plugin %q", plugin.ID)
-			}
+		if err = runPlugins(ctx, "pre", _package.Plugins.Pre, pluginsDir, project.Path, manifest); err != nil {
+			return report, err
 		}
 	}
 
 	// Create project in filesystem; meaning file structure and templates
 	if _package.DirTree != nil {
-		// Create template engine using default tags.
+		// Create template engine using default tags. The engine shares the answers with the caller.
 		tmpl := templates.NewEngine("", "")
 		tmpl.MissingKeyFn = missingTemplateKeyFn
+		if opts.MissingKeyFn != nil {
+			tmpl.MissingKeyFn = opts.MissingKeyFn
+		}
+		tmpl.SetValues(opts.Answers)
+		defer func() {
+			for key, value := range tmpl.Values {
+				opts.Answers[key] = value
+			}
+		}()
+
+		// The builder operates on a filesystem that is rooted at the project directory
+		build := builder.NewOS(project.Path, templatesDir, tmpl)
+		build.Conflicts = opts.Conflicts
+		build.PromptConflict = promptConflict
+		build.Report = report
 
 		logger.Infof("Creating project structure")
-		for _, entry := range _package.DirTree.Entries {
-			if err = createEntry(ctx, entry, templatesDir, tmpl); err != nil {
-				return errors.Wrapf(err, "create directory tree entry %q", entry.Path)
-			}
+		if err = build.CreateEntries(ctx, _package.DirTree.Entries); err != nil {
+			return report, err
+		}
+
+		manifest.Files = build.Files
+		manifest.Answers = make(map[string]string, len(tmpl.Values))
+		for key, value := range tmpl.Values {
+			manifest.Answers[key] = value
 		}
 	}
 
 	// Post-run plugins
 	if _package.Plugins != nil {
-		for _, plugin := range _package.Plugins.Post {
-			if err = runPlugin(ctx, plugin, pluginsDir); err != nil {
-				return errors.Wrapf(err, "run post-run plugin %q", plugin.ID)
+		if err = runPlugins(ctx, "post", _package.Plugins.Post, pluginsDir, project.Path, manifest); err != nil {
+			return report, err
+		}
+	}
+
+	// Notes may refer to answers of the build; they get printed once the project was created. The project is complete
+	// at this point, so notes that fail to render are only left out.
+	if opts.Summary && _package.Notes != nil {
+		missingKeyFn := missingTemplateKeyFn
+		if opts.MissingKeyFn != nil {
+			missingKeyFn = opts.MissingKeyFn
+		}
+		for key, value := range manifest.Answers {
+			opts.Answers[key] = value
+		}
+
+		notes, values, nerr := renderNotes(ctx, project, *_package.Notes, opts.Answers, missingKeyFn)
+		if nerr != nil {
+			logger.Errorf("Failed to render notes of package %q: %v", _package.Label, nerr)
+		} else {
+			opts.Notes = notes
+			for key, value := range values {
+				opts.Answers[key] = value
 			}
 		}
 	}
 
+	project.Manifest = manifest
+
+	return report, nil
+}
+
+// findProjectByPath returns the tracked project at the given path. It returns nil if no such project exists.
+func findProjectByPath(ctx context.Context, prma projects.Manager, path string) (*domain.Project, error) {
+	projectList, err := prma.Fetch(ctx)
+	if errors.Is(err, database.ErrBucketNotFound) {
+		return nil, nil // No projects stored yet
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch projects")
+	}
+
+	for idx := range projectList {
+		if projectList[idx].Path == path {
+			return &projectList[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordAppliedPackage records the build of project, which was scaffolded into the root of the tracked project, in the
+// history of the tracked project. The answers of the build are added to the tracked project's answers.
+func recordAppliedPackage(
+	ctx context.Context, prma projects.Manager, tracked *domain.Project, project *domain.ProjectAdd,
+	answers map[string]string,
+) error {
+	merged := make(map[string]string, len(tracked.Answers)+len(answers))
+	for key, value := range tracked.Answers {
+		merged[key] = value
+	}
+	for key, value := range answers {
+		merged[key] = value
+	}
+
+	history := append(tracked.History, &domain.ProjectHistoryEntry{
+		Package:   project.Package,
+		Manifest:  project.Manifest,
+		AppliedAt: time.Now(),
+	})
+
+	simplog.FromContext(ctx).Debugf("updating project %q", tracked.ID)
+	err := prma.Update(ctx, &domain.ProjectUpdate{
+		ID:      tracked.ID,
+		Answers: merged,
+		History: history,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "update project %q", tracked.ID)
+	}
+
 	return nil
 }
 
-func newProject(ctx context.Context, packageLabel, name string) error {
+func newProject(ctx context.Context, packageLabel, name string, opts *buildOptions) error {
 	logger := simplog.FromContext(ctx)
 
 	// Get project manager from session
@@ -274,23 +611,84 @@ func newProject(ctx context.Context, packageLabel, name string) error {
 		return errors.Wrapf(err, "get absolute path to project %q", name)
 	}
 
+	// When scaffolding into an existing directory, the given path might be something like '.'; use the directory's
+	// name as the project's name instead.
+	if opts != nil && opts.Into {
+		path = filepath.Clean(path)
+		name = filepath.Base(path)
+	}
+	if opts != nil && opts.Name != "" {
+		name = opts.Name
+	}
+
 	// Create project from package at path
 	project := domain.NewProject(packageLabel, path, name)
 
-	err = buildProject(ctx, project)
+	if opts == nil {
+		opts = &buildOptions{Conflicts: builder.ConflictFail}
+	}
+	if opts.Answers == nil {
+		opts.Answers = make(map[string]string)
+	}
+
+	report, err := buildProject(ctx, project, opts)
+	if opts.Into && report != nil {
+		if rerr := renderReport(report); rerr != nil {
+			logger.Errorf("Failed to render build report: %v", rerr)
+		}
+	}
 	if err != nil {
 		return errors.Wrapf(err, "build project %q at %q from %q", project.Name, project.Path, project.Package)
 	}
 
-	// Store project through project manager
-	logger.Debug("storing project %q in project manager", project.Name)
+	// An existing directory might already be tracked by proji; in that case, the package gets recorded in the tracked
+	// project's history, like packages that are applied by 'proji add'.
+	if opts.Into {
+		tracked, err := findProjectByPath(ctx, prma, project.Path)
+		if err != nil {
+			return errors.Wrapf(err, "check if project %q is already tracked", project.Path)
+		}
+		if tracked != nil {
+			if err = recordAppliedPackage(ctx, prma, tracked, project, opts.Answers); err != nil {
+				return err
+			}
+
+			logger.Infof("Successfully applied package %q to tracked project %q", project.Package, tracked.Name)
+			if opts.Summary {
+				if err = printSummary(project, opts.Notes, false); err != nil {
+					logger.Errorf("Failed to print project summary: %v", err)
+				}
+			}
+
+			return joinWorkspace(ctx, opts.Workspace, tracked.ID)
+		}
+	}
+
+	// Store project through project manager. The answers are kept so that they can be reused later on, for example
+	// when applying further packages to the project.
+	logger.Debugf("storing project %q in project manager", project.Name)
+	project.Answers = opts.Answers
 
+	// Layered packages are part of the build; the manifest already lists them. The history only records packages that
+	// were applied later on.
 	err = prma.Store(ctx, project)
 	if err != nil {
 		return errors.Wrapf(err, "store project %q in project manager", project.Name)
 	}
 
+	writeProjectMarker(ctx, project)
+
 	logger.Infof("Successfully created project %q", project.Path)
 
+	if err = joinWorkspace(ctx, opts.Workspace, project.ID); err != nil {
+		return err
+	}
+
+	if opts.Summary {
+		if err = printSummary(project, opts.Notes, !opts.Into); err != nil {
+			logger.Errorf("Failed to print project summary: %v", err)
+		}
+	}
+
 	return nil
 }
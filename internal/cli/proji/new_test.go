@@ -0,0 +1,46 @@
+package proji
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestRecordAppliedPackage(t *testing.T) {
+	t.Parallel()
+
+	tracked := &domain.Project{
+		ID: "abc", Name: "app", Path: "/app", Package: "go",
+		Answers: map[string]string{"author": "jane", "license": "MIT"},
+		History: []*domain.ProjectHistoryEntry{{Package: "dkr", Subpath: "deploy"}},
+	}
+	prma := &memoryProjects{projects: map[string]*domain.Project{"abc": tracked}}
+
+	manifest := &domain.ProjectManifest{Packages: []*domain.ManifestPackage{{Label: "gha", Revision: 2}}}
+	applied := domain.NewProject("gha", "/app", "app")
+	applied.Manifest = manifest
+
+	err := recordAppliedPackage(context.Background(), prma, tracked, applied, map[string]string{"license": "MIT-0"})
+	if err != nil {
+		t.Fatalf("recordAppliedPackage() error = %v", err)
+	}
+
+	got := prma.projects["abc"]
+	wantHistory := []*domain.ProjectHistoryEntry{
+		{Package: "dkr", Subpath: "deploy"},
+		{Package: "gha", Manifest: manifest},
+	}
+	if diff := cmp.Diff(wantHistory, got.History,
+		cmpopts.IgnoreFields(domain.ProjectHistoryEntry{}, "AppliedAt")); diff != "" {
+		t.Fatalf("history mismatch (-want +got):\n%s", diff)
+	}
+
+	wantAnswers := map[string]string{"author": "jane", "license": "MIT-0"}
+	if diff := cmp.Diff(wantAnswers, got.Answers); diff != "" {
+		t.Fatalf("answers mismatch (-want +got):\n%s", diff)
+	}
+}
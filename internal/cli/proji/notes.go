@@ -0,0 +1,110 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+// maxTreeEntries limits the number of entries that are shown in the tree summary of a new project. Plugins might
+// create large directories, e.g. by installing dependencies, which would otherwise flood the terminal.
+const maxTreeEntries = 50
+
+// renderNotes renders the notes of a package. The notes may use the same template variables as the package's files;
+// the values that were collected during the build are reused and missing ones are resolved through missingKeyFn.
+// The name of the project is always available as 'project-name'.
+func renderNotes(ctx context.Context, project *domain.ProjectAdd, notes string, values map[string]string,
+	missingKeyFn templates.MissingKeyFn,
+) (string, map[string]string, error) {
+	tmpl := templates.NewEngine("", "")
+	tmpl.MissingKeyFn = missingKeyFn
+	tmpl.SetValues(map[string]string{"project-name": project.Name})
+	tmpl.SetValues(values)
+
+	rendered, err := tmpl.ParseToString(ctx, strings.TrimSpace(notes))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "render notes")
+	}
+
+	return rendered, tmpl.Values, nil
+}
+
+// projectTree returns the entries below root as slash separated paths; directories end with a slash. The project
+// marker is left out and version control directories are not descended into. At most maxTreeEntries entries are
+// returned, together with the number of entries that were left out.
+func projectTree(root string) ([]string, int, error) {
+	var paths []string
+	omitted := 0
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == projects.MarkerFile {
+			return nil
+		}
+
+		if len(paths) >= maxTreeEntries {
+			omitted++
+		} else if entry.IsDir() {
+			paths = append(paths, rel+"/")
+		} else {
+			paths = append(paths, rel)
+		}
+
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "walk %q", root)
+	}
+
+	return paths, omitted, nil
+}
+
+// printSummary prints what was created for a new project, followed by the notes of its package. The tree is left
+// out if the project was scaffolded into an existing directory; the build report covers that case.
+func printSummary(project *domain.ProjectAdd, notes string, withTree bool) error {
+	if withTree {
+		paths, omitted, err := projectTree(project.Path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		if err = text.RenderTree(os.Stdout, filepath.Base(project.Path), paths); err != nil {
+			return errors.Wrap(err, "render project tree")
+		}
+		if omitted > 0 {
+			fmt.Printf("... and %d more\n", omitted)
+		}
+	}
+
+	if notes != "" {
+		fmt.Printf("\nNext steps:\n\n%s\n", notes)
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package proji
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/packages/portability/importing"
+)
+
+type (
+	// outdatedStatus describes how a project relates to one of the packages it was built from.
+	outdatedStatus string
+
+	// outdatedEntry compares the package revision that a project was built from with the installed package.
+	outdatedEntry struct {
+		ProjectID         string         `json:"project_id"`
+		ProjectName       string         `json:"project_name"`
+		ProjectPath       string         `json:"project_path"`
+		Package           string         `json:"package"`
+		Subpath           string         `json:"subpath,omitempty"` // Set for packages that were applied to a subpath
+		Status            outdatedStatus `json:"status"`
+		BuiltRevision     int            `json:"built_revision,omitempty"`
+		InstalledRevision int            `json:"installed_revision,omitempty"`
+		Behind            int            `json:"behind"`
+		BuiltSHA          *string        `json:"built_sha,omitempty"`
+		InstalledSHA      *string        `json:"installed_sha,omitempty"`
+		UpstreamSHA       *string        `json:"upstream_sha,omitempty"`
+		Error             string         `json:"error,omitempty"`
+	}
+)
+
+const (
+	outdatedUpToDate        outdatedStatus = "up-to-date"
+	outdatedBehind          outdatedStatus = "outdated"         // The installed package is newer
+	outdatedUpstreamChanged outdatedStatus = "upstream-changed" // The installed package is outdated itself
+	outdatedMissing         outdatedStatus = "missing"          // The package is no longer installed
+	outdatedUnknown         outdatedStatus = "unknown"          // The project has no build manifest
+)
+
+func projectOutdatedCommand() *cobra.Command {
+	var all, upstream, asJSON bool
+
+	cmd := &cobra.Command{
+		Use:                   "outdated [OPTIONS]",
+		Short:                 "List projects that were built from older package revisions",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji outdated
+  proji outdated --upstream
+  proji outdated --all --json`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listOutdated(cmd.Context(), all, upstream, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Also list projects that are up-to-date")
+	cmd.Flags().BoolVar(&upstream, "upstream", false, "Also check the upstream SHA of packages with an upstream URL")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+
+	return cmd
+}
+
+// upstreamSHAs resolves and caches the upstream SHAs of packages.
+type upstreamSHAs struct {
+	cache map[string]*string
+	errs  map[string]error
+}
+
+// get returns the current upstream SHA of the given package. It returns nil if the package has no upstream.
+func (u *upstreamSHAs) get(ctx context.Context, _package *domain.Package) (*string, error) {
+	if _package.UpstreamURL == nil || *_package.UpstreamURL == "" {
+		return nil, nil
+	}
+
+	if sha, exists := u.cache[_package.Label]; exists {
+		return sha, u.errs[_package.Label]
+	}
+
+	simplog.FromContext(ctx).Debugf("fetching upstream package %q", *_package.UpstreamURL)
+	upstreamPkg, err := importing.RemotePackage(ctx, *_package.UpstreamURL)
+	if err != nil {
+		err = errors.Wrapf(err, "fetch upstream of package %q", _package.Label)
+		u.cache[_package.Label], u.errs[_package.Label] = nil, err
+
+		return nil, err
+	}
+
+	u.cache[_package.Label] = upstreamPkg.SHA
+
+	return upstreamPkg.SHA, nil
+}
+
+// shaDiffers checks whether both SHAs are known and differ.
+func shaDiffers(a, b *string) bool {
+	return a != nil && b != nil && *a != "" && *b != "" && *a != *b
+}
+
+// newOutdatedEntry returns an up-to-date entry for the given package of the project. Packages that were applied to a
+// subpath of the project, e.g. by 'proji add', carry that subpath.
+func newOutdatedEntry(project *domain.Project, label, subpath string) *outdatedEntry {
+	return &outdatedEntry{
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		ProjectPath: project.Path,
+		Package:     label,
+		Subpath:     subpath,
+		Status:      outdatedUpToDate,
+	}
+}
+
+// comparePackage compares the given entry with the installed version of its package.
+func comparePackage(ctx context.Context, entry *outdatedEntry, installed map[string]*domain.Package,
+	upstream *upstreamSHAs,
+) {
+	_package, exists := installed[entry.Package]
+	if !exists {
+		entry.Status = outdatedMissing
+		return
+	}
+
+	entry.InstalledRevision = _package.Revision
+	entry.InstalledSHA = _package.SHA
+	if entry.InstalledRevision > entry.BuiltRevision {
+		entry.Behind = entry.InstalledRevision - entry.BuiltRevision
+	}
+	if entry.Behind > 0 || shaDiffers(entry.BuiltSHA, entry.InstalledSHA) {
+		entry.Status = outdatedBehind
+	}
+
+	if upstream == nil {
+		return
+	}
+
+	sha, err := upstream.get(ctx, _package)
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+
+	entry.UpstreamSHA = sha
+	if entry.Status == outdatedUpToDate && shaDiffers(entry.InstalledSHA, entry.UpstreamSHA) {
+		entry.Status = outdatedUpstreamChanged
+	}
+}
+
+// compareManifest compares the packages of a manifest with the installed packages. Without a manifest, a single entry
+// of unknown status is returned for the given label.
+func compareManifest(
+	ctx context.Context, project *domain.Project, manifest *domain.ProjectManifest, label, subpath string,
+	installed map[string]*domain.Package, upstream *upstreamSHAs,
+) []*outdatedEntry {
+	if manifest == nil {
+		entry := newOutdatedEntry(project, label, subpath)
+		entry.Status = outdatedUnknown
+		entry.Error = "no build manifest"
+
+		return []*outdatedEntry{entry}
+	}
+
+	entries := make([]*outdatedEntry, 0, len(manifest.Packages))
+	for _, built := range manifest.Packages {
+		entry := newOutdatedEntry(project, built.Label, subpath)
+		entry.BuiltRevision = built.Revision
+		entry.BuiltSHA = built.SHA
+
+		comparePackage(ctx, entry, installed, upstream)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// compareProject compares the packages that the project was built from, and the packages that were applied to it
+// later on, with the installed packages.
+func compareProject(
+	ctx context.Context, project *domain.Project, installed map[string]*domain.Package, upstream *upstreamSHAs,
+) []*outdatedEntry {
+	entries := compareManifest(ctx, project, project.Manifest, project.Package, "", installed, upstream)
+
+	for _, applied := range project.History {
+		if applied == nil {
+			continue
+		}
+
+		entries = append(entries,
+			compareManifest(ctx, project, applied.Manifest, applied.Package, applied.Subpath, installed, upstream)...)
+	}
+
+	return entries
+}
+
+func shortSHA(sha *string) string {
+	if sha == nil {
+		return ""
+	}
+	if len(*sha) > 7 {
+		return (*sha)[:7]
+	}
+
+	return *sha
+}
+
+func renderOutdated(entries []*outdatedEntry, upstream bool) error {
+	if len(entries) == 0 {
+		fmt.Println("\nAll projects are up-to-date")
+		return nil
+	}
+
+	fmt.Println()
+	table := text.NewTablePrinter()
+
+	header := []any{"#", "ID", "Project", "Package", "Built", "Installed", "Behind", "Status"}
+	if upstream {
+		header = append(header, "Upstream")
+	}
+	table.AddHeaderColumns(header...)
+
+	for idx, entry := range entries {
+		_package := entry.Package
+		if entry.Subpath != "" {
+			_package = fmt.Sprintf("%s (%s)", entry.Package, entry.Subpath)
+		}
+
+		row := []any{
+			idx + 1, entry.ProjectID, entry.ProjectName, _package, entry.BuiltRevision, entry.InstalledRevision,
+			entry.Behind, entry.Status,
+		}
+		if upstream {
+			upstreamColumn := shortSHA(entry.UpstreamSHA)
+			if entry.Error != "" {
+				upstreamColumn = entry.Error
+			}
+			row = append(row, upstreamColumn)
+		}
+		table.AddRow(row...)
+	}
+
+	return table.Render()
+}
+
+func listOutdated(ctx context.Context, all, upstream, asJSON bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get managers from session
+	logger.Debug("getting project and package manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+	pama := session.PackageManager
+	if pama == nil {
+		return errors.New("no package manager found")
+	}
+
+	logger.Debug("fetching project list")
+	projectList, err := prma.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return errors.Wrap(err, "fetch projects")
+	}
+
+	logger.Debug("fetching package list")
+	packageList, err := pama.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return errors.Wrap(err, "fetch packages")
+	}
+
+	installed := make(map[string]*domain.Package, len(packageList))
+	for idx := range packageList {
+		installed[packageList[idx].Label] = &packageList[idx]
+	}
+
+	var upstreamCache *upstreamSHAs
+	if upstream {
+		upstreamCache = &upstreamSHAs{cache: make(map[string]*string), errs: make(map[string]error)}
+	}
+
+	entries := make([]*outdatedEntry, 0, len(projectList))
+	for idx := range projectList {
+		for _, entry := range compareProject(ctx, &projectList[idx], installed, upstreamCache) {
+			if all || entry.Status != outdatedUpToDate {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+	}
+
+	return renderOutdated(entries, upstream)
+}
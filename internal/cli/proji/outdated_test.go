@@ -0,0 +1,139 @@
+package proji
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestCompareProject(t *testing.T) {
+	t.Parallel()
+
+	sha := func(s string) *string { return &s }
+
+	installed := map[string]*domain.Package{
+		"go":  {Label: "go", Revision: 3},
+		"dkr": {Label: "dkr", Revision: 1, SHA: sha("bbbbbbb")},
+		"gha": {Label: "gha", Revision: 2, SHA: sha("ccccccc"), UpstreamURL: sha("https://example.com/gha")},
+	}
+
+	manifest := func(packages ...*domain.ManifestPackage) *domain.ProjectManifest {
+		return &domain.ProjectManifest{Packages: packages}
+	}
+
+	cases := []struct {
+		name     string
+		project  *domain.Project
+		upstream *upstreamSHAs
+		want     []*outdatedEntry
+	}{
+		{
+			name: "up-to-date",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "go",
+				Manifest: manifest(&domain.ManifestPackage{Label: "go", Revision: 3}),
+			},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "go", Status: outdatedUpToDate,
+				BuiltRevision: 3, InstalledRevision: 3,
+			}},
+		},
+		{
+			name: "behind",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "go",
+				Manifest: manifest(&domain.ManifestPackage{Label: "go", Revision: 1}),
+			},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "go", Status: outdatedBehind,
+				BuiltRevision: 1, InstalledRevision: 3, Behind: 2,
+			}},
+		},
+		{
+			name: "missing",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "rs",
+				Manifest: manifest(&domain.ManifestPackage{Label: "rs", Revision: 1}),
+			},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "rs", Status: outdatedMissing,
+				BuiltRevision: 1,
+			}},
+		},
+		{
+			name:    "no manifest",
+			project: &domain.Project{ID: "1", Name: "app", Path: "/app", Package: "go"},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "go", Status: outdatedUnknown,
+				Error: "no build manifest",
+			}},
+		},
+		{
+			name: "sha mismatch",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "dkr",
+				Manifest: manifest(&domain.ManifestPackage{Label: "dkr", Revision: 1, SHA: sha("aaaaaaa")}),
+			},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "dkr", Status: outdatedBehind,
+				BuiltRevision: 1, InstalledRevision: 1, BuiltSHA: sha("aaaaaaa"), InstalledSHA: sha("bbbbbbb"),
+			}},
+		},
+		{
+			name: "upstream changed",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "gha",
+				Manifest: manifest(&domain.ManifestPackage{Label: "gha", Revision: 2, SHA: sha("ccccccc")}),
+			},
+			upstream: &upstreamSHAs{
+				cache: map[string]*string{"gha": sha("ddddddd")},
+				errs:  map[string]error{},
+			},
+			want: []*outdatedEntry{{
+				ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "gha",
+				Status: outdatedUpstreamChanged, BuiltRevision: 2, InstalledRevision: 2, BuiltSHA: sha("ccccccc"),
+				InstalledSHA: sha("ccccccc"), UpstreamSHA: sha("ddddddd"),
+			}},
+		},
+		{
+			name: "history",
+			project: &domain.Project{
+				ID: "1", Name: "app", Path: "/app", Package: "go",
+				Manifest: manifest(&domain.ManifestPackage{Label: "go", Revision: 3}),
+				History: []*domain.ProjectHistoryEntry{
+					{Package: "go", Subpath: "svc", Manifest: manifest(&domain.ManifestPackage{Label: "go", Revision: 2})},
+					{Package: "dkr"},
+				},
+			},
+			want: []*outdatedEntry{
+				{
+					ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "go", Status: outdatedUpToDate,
+					BuiltRevision: 3, InstalledRevision: 3,
+				},
+				{
+					ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "go", Subpath: "svc",
+					Status: outdatedBehind, BuiltRevision: 2, InstalledRevision: 3, Behind: 1,
+				},
+				{
+					ProjectID: "1", ProjectName: "app", ProjectPath: "/app", Package: "dkr", Status: outdatedUnknown,
+					Error: "no build manifest",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := compareProject(context.Background(), tc.project, installed, tc.upstream)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("compareProject() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -106,7 +106,7 @@ func editPackage(ctx context.Context, label, fileType string) error {
 	_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
 
 	// When we are done editing, we can import the edited package
-	if err := replacePackage(ctx, label, path); err != nil {
+	if err := replacePackage(ctx, label, path, "edit"); err != nil {
 		return errors.Wrap(err, "replace package")
 	}
 
@@ -73,6 +73,8 @@ func exportExample(ctx context.Context, destination, fileType string) error {
 		Label:       "xxx",
 		Name:        "Example",
 		Description: pointer.To("This is an example package"),
+		Destination: pointer.To("~/code/%{{project-name}}%"),
+		Notes:       pointer.To("Run 'go run ./src' to start %{{project-name}}%"),
 		DirTree: &domain.DirTreeConfig{
 			Entries: []*domain.DirEntryConfig{
 				{IsDir: true, Path: "docs"},
@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/diff"
+	"github.com/nikoksr/proji/pkg/packages"
+	"github.com/nikoksr/proji/pkg/packages/portability"
+	"github.com/nikoksr/proji/pkg/packages/portability/exporting"
+)
+
+func newHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "history LABEL",
+		Short:                 "List the revisions of an installed package",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Long: `Every change of a package, e.g. through 'proji package edit' or 'proji package replace', is kept as a
+numbered revision. Revisions can be compared with 'proji package diff' and restored with 'proji package rollback'.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listRevisions(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func newDiffCommand() *cobra.Command {
+	var fileType string
+
+	cmd := &cobra.Command{
+		Use:                   "diff [OPTIONS] LABEL REV1 [REV2]",
+		Short:                 "Compare two revisions of an installed package",
+		Args:                  cobra.RangeArgs(2, 3),
+		DisableFlagsInUseLine: true,
+
+		Long: `Shows the changes between two revisions of a package as a unified diff of their configs. If REV2 is
+omitted, REV1 is compared with the current revision.`,
+
+		Example: `  proji package diff go 1 3
+  proji package diff -t json go 2`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revisions, err := parseRevisions(args[1:]...)
+			if err != nil {
+				return err
+			}
+
+			return diffRevisions(cmd.Context(), args[0], fileType, revisions...)
+		},
+	}
+
+	cmd.Flags().StringVarP(&fileType, "type", "t", "toml", "File type in which to compare the revisions (toml, json)")
+
+	return cmd
+}
+
+func newRollbackCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "rollback LABEL REV",
+		Short:                 "Restore an older revision of an installed package",
+		Args:                  cobra.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+
+		Long: `Restores the given revision of a package. The history is kept as is; the restored definition is recorded
+as a new revision, so that a rollback can be undone like any other change.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revisions, err := parseRevisions(args[1])
+			if err != nil {
+				return err
+			}
+
+			return rollbackPackage(cmd.Context(), args[0], revisions[0])
+		},
+	}
+
+	return cmd
+}
+
+// parseRevisions parses the given revision numbers.
+func parseRevisions(args ...string) ([]int, error) {
+	revisions := make([]int, 0, len(args))
+	for _, arg := range args {
+		revision, err := strconv.Atoi(arg)
+		if err != nil || revision < 1 {
+			return nil, errors.Newf("invalid revision %q; expected a positive number", arg)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func packageManager(ctx context.Context) (packages.Manager, error) {
+	logger := simplog.FromContext(ctx)
+
+	// Get package manager from session
+	logger.Debug("getting package manager from cli session")
+	pama := cli.SessionFromContext(ctx).PackageManager
+	if pama == nil {
+		return nil, errors.New("no package manager available")
+	}
+
+	return pama, nil
+}
+
+func listRevisions(ctx context.Context, label string) error {
+	logger := simplog.FromContext(ctx)
+
+	pama, err := packageManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("fetching history of package %q", label)
+	history, err := pama.History(ctx, label)
+	if err != nil {
+		return errors.Wrapf(err, "get history of package %q", label)
+	}
+
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("Revision", "Name", "Created at", "Source", "")
+
+	for idx, revision := range history {
+		current := ""
+		if idx == len(history)-1 {
+			current = "current"
+		}
+
+		source := revision.Source
+		if source == "" {
+			source = "-"
+		}
+
+		name := ""
+		if revision.Package != nil {
+			name = revision.Package.Name
+		}
+
+		createdAt := revision.CreatedAt.Local().Format("2006-01-02 15:04")
+		table.AddRow(revision.Revision, name, createdAt, source, current)
+	}
+
+	if err = table.Render(); err != nil {
+		return errors.Wrap(err, "render table")
+	}
+
+	return nil
+}
+
+// encodeRevision returns the config of the package of a revision.
+func encodeRevision(revision *domain.PackageRevision, fileType string) (string, error) {
+	if revision.Package == nil {
+		return "", errors.Newf("revision %d has no package", revision.Revision)
+	}
+
+	encoded, err := exporting.Encode(revision.Package.ToConfig(), fileType)
+	if err != nil {
+		return "", errors.Wrapf(err, "encode revision %d", revision.Revision)
+	}
+
+	return string(encoded), nil
+}
+
+func diffRevisions(ctx context.Context, label, fileType string, revisions ...int) error {
+	logger := simplog.FromContext(ctx)
+
+	if fileType != portability.FileTypeTOML && fileType != portability.FileTypeJSON {
+		return portability.ErrUnsupportedConfigFileType
+	}
+
+	pama, err := packageManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Without a second revision, the first one is compared with the current one.
+	if len(revisions) < 2 {
+		current, err := pama.GetByLabel(ctx, label)
+		if err != nil {
+			return errors.Wrapf(err, "get package %q", label)
+		}
+
+		revisions = append(revisions, current.Revision)
+	}
+
+	configs := make([]string, 0, len(revisions))
+	for _, number := range revisions {
+		logger.Debugf("loading revision %d of package %q", number, label)
+		revision, err := pama.GetRevision(ctx, label, number)
+		if err != nil {
+			return errors.Wrapf(err, "get revision %d of package %q", number, label)
+		}
+
+		config, err := encodeRevision(&revision, fileType)
+		if err != nil {
+			return err
+		}
+
+		configs = append(configs, config)
+	}
+
+	aName := fmt.Sprintf("a/%s@%d.%s", label, revisions[0], fileType)
+	bName := fmt.Sprintf("b/%s@%d.%s", label, revisions[1], fileType)
+
+	unified := diff.Unified(aName, bName, configs[0], configs[1], 3)
+	if unified == "" {
+		logger.Infof("Revisions %d and %d of %q are identical", revisions[0], revisions[1], label)
+		return nil
+	}
+
+	fmt.Print(unified)
+
+	return nil
+}
+
+func rollbackPackage(ctx context.Context, label string, revision int) error {
+	logger := simplog.FromContext(ctx)
+
+	pama, err := packageManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("rolling back package %q to revision %d", label, revision)
+	if err = pama.Rollback(ctx, label, revision); err != nil {
+		return errors.Wrapf(err, "roll back package %q to revision %d", label, revision)
+	}
+
+	logger.Infof("Successfully rolled back package %q to revision %d", label, revision)
+
+	return nil
+}
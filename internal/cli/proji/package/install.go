@@ -101,6 +101,7 @@ func installPackages(ctx context.Context, paths ...string) error {
 		}
 
 		logger.Debugf("adding package %q", _package.Label)
+		_package.Source = path
 		if err = pama.Store(ctx, _package); err != nil {
 			return errors.Wrapf(err, "store %q, imported from %q", _package.Name, path)
 		}
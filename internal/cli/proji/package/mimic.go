@@ -71,6 +71,7 @@ func mimicPackages(ctx context.Context, exclude string, paths ...string) error {
 		}
 
 		logger.Debugf("adding package %q", pkg.Label)
+		pkg.Source = "mimic of " + path
 		if err = pama.Store(ctx, pkg); err != nil {
 			return errors.Wrapf(err, "store %q, imported as mimic of %q", pkg.Name, path)
 		}
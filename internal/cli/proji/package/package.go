@@ -12,12 +12,15 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(
 		newImportCommand(),
+		newDiffCommand(),
 		newEditCommand(),
 		newExportCommand(),
+		newHistoryCommand(),
 		newListCommand(),
 		newMimicCommand(),
 		newRemoveCommand(),
 		newReplaceCommand(),
+		newRollbackCommand(),
 		newShowCommand(),
 	)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
 )
 
 func newReplaceCommand() *cobra.Command {
@@ -19,8 +20,12 @@ func newReplaceCommand() *cobra.Command {
 		Args:                  cobra.ExactArgs(2),
 		DisableFlagsInUseLine: true,
 
+		Long: `Replaces the package LABEL with the package of the config file at PATH. If the new package keeps the label,
+the installed package is updated in place; it keeps its creation date and its revision is bumped. Otherwise the old
+package is removed and the new one is installed.`,
+
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return replacePackage(cmd.Context(), args[0], args[1])
+			return replacePackage(cmd.Context(), args[0], args[1], args[1])
 		},
 	}
 
@@ -29,7 +34,9 @@ func newReplaceCommand() *cobra.Command {
 	return cmd
 }
 
-func replacePackage(ctx context.Context, label, config string) error {
+// replacePackage replaces the package with the given label by the package defined in the config file. The source is
+// recorded in the package's revision history.
+func replacePackage(ctx context.Context, label, config, source string) error {
 	logger := simplog.FromContext(ctx)
 
 	// Get package manager from session
@@ -55,6 +62,31 @@ func replacePackage(ctx context.Context, label, config string) error {
 		return errors.Wrapf(err, "load new package from config file %q", config)
 	}
 
+	// A package that keeps its label gets updated in place; this preserves its creation date and bumps its revision,
+	// so that projects built from an older revision can be told apart.
+	if newPkg.Label == pkg.Label {
+		logger.Debugf("updating package %q", pkg.Label)
+		err = pama.Update(ctx, &domain.PackageUpdate{
+			Label:       newPkg.Label,
+			Name:        newPkg.Name,
+			UpstreamURL: newPkg.UpstreamURL,
+			SHA:         newPkg.SHA,
+			Description: newPkg.Description,
+			Destination: newPkg.Destination,
+			Notes:       newPkg.Notes,
+			DirTree:     newPkg.DirTree,
+			Plugins:     newPkg.Plugins,
+			Source:      source,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "update package %q", pkg.Label)
+		}
+
+		logger.Infof("Successfully replaced package %q", pkg.Label)
+
+		return nil
+	}
+
 	// Remove the old package
 	logger.Debugf("removing package %q", label)
 	if err := pama.Remove(ctx, pkg.Label); err != nil {
@@ -63,6 +95,7 @@ func replacePackage(ctx context.Context, label, config string) error {
 
 	// Install the new package
 	logger.Debugf("installing package %q", newPkg.Label)
+	newPkg.Source = source
 	if err := pama.Store(ctx, newPkg); err != nil {
 		return errors.Wrapf(err, "store package %q", newPkg.Label)
 	}
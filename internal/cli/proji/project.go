@@ -0,0 +1,26 @@
+package proji
+
+import "github.com/spf13/cobra"
+
+// projectCommand returns a new instance of the project command. It groups commands that work on already tracked
+// projects.
+func projectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "project",
+		Aliases: []string{"prj"},
+		Short:   "Manage tracked projects",
+	}
+
+	cmd.AddCommand(
+		projectAdoptCommand(),
+		projectArchiveCommand(),
+		projectEditCommand(),
+		projectMoveCommand(),
+		projectRelocateCommand(),
+		projectRestoreCommand(),
+		projectStatsCommand(),
+		projectUpgradeCommand(),
+	)
+
+	return cmd
+}
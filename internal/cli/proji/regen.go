@@ -0,0 +1,187 @@
+package proji
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func projectRegenCommand() *cobra.Command {
+	var runPlugins, baseOnly bool
+
+	cmd := &cobra.Command{
+		Use:                   "regen [OPTIONS] ID DEST",
+		Short:                 "Regenerate a project from its build manifest",
+		Args:                  cobra.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji regen cf1l3q4bvs0e0m0ibmcg /tmp/my-project
+  proji regen --base-only cf1l3q4bvs0e0m0ibmcg ./pristine`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return regenProject(cmd.Context(), args[0], args[1], runPlugins, baseOnly)
+		},
+	}
+
+	cmd.Flags().BoolVar(&runPlugins, "plugins", false, "Run the recorded plugins again")
+	cmd.Flags().BoolVar(&baseOnly, "base-only", false, "Skip packages that were applied later on, e.g. by 'proji add'")
+
+	return cmd
+}
+
+// writeManifestFiles writes the directories and files of the manifest into fs. Paths are prefixed with subpath. Files
+// whose content was not recorded are skipped.
+func writeManifestFiles(
+	ctx context.Context, fs afero.Fs, manifest *domain.ProjectManifest, subpath string,
+) (int, error) {
+	logger := simplog.FromContext(ctx)
+
+	written := 0
+	for _, file := range manifest.Files {
+		filePath := filepath.FromSlash(path.Join(filepath.ToSlash(subpath), file.Path))
+
+		if file.IsDir {
+			if err := fs.MkdirAll(filePath, 0o755); err != nil {
+				return written, errors.Wrapf(err, "create directory %q", filePath)
+			}
+
+			continue
+		}
+
+		if !file.HasContent() {
+			logger.Warnf("Content of file %q was not recorded; skipping", filePath)
+			continue
+		}
+
+		if err := fs.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return written, errors.Wrapf(err, "create directory %q", filepath.Dir(filePath))
+		}
+		if err := afero.WriteFile(fs, filePath, file.Content, 0o644); err != nil {
+			return written, errors.Wrapf(err, "write file %q", filePath)
+		}
+
+		written++
+	}
+
+	return written, nil
+}
+
+// runManifestPlugins runs the recorded plugins of the given stage inside of dir.
+func runManifestPlugins(ctx context.Context, manifest *domain.ProjectManifest, stage, dir string) error {
+	for _, plugin := range manifest.Plugins {
+		if plugin.Stage != stage {
+			continue
+		}
+
+		if _, err := runPlugin(ctx, &domain.Plugin{Path: plugin.Path}, "", dir); err != nil {
+			return errors.Wrapf(err, "run %s-run plugin %q", stage, plugin.Path)
+		}
+	}
+
+	return nil
+}
+
+func regenProject(ctx context.Context, projectID, dest string, runPlugins, baseOnly bool) (err error) {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	project, err := prma.GetByID(ctx, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "get project %q", projectID)
+	}
+	if project.Manifest == nil {
+		return errors.Newf("project %q has no build manifest; it was created by an older version of proji", project.Name)
+	}
+
+	// Get absolute path to destination; it must not exist yet
+	dest, err = localPathToAbsPath(strings.TrimSpace(dest))
+	if err != nil {
+		return errors.Wrapf(err, "get absolute path to %q", dest)
+	}
+
+	logger.Infof("Creating base directory %q", dest)
+	if err = os.Mkdir(dest, 0o755); err != nil {
+		if os.IsExist(err) {
+			return errors.Newf("path %q already exists", dest)
+		}
+
+		return errors.Wrapf(err, "create directory %q", dest)
+	}
+
+	// The destination was created by this run; don't leave a partially regenerated project behind
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		logger.Debugf("removing partially regenerated project %q", dest)
+		if rerr := os.RemoveAll(dest); rerr != nil {
+			logger.Errorf("Failed to remove %q: %v", dest, rerr)
+		}
+	}()
+
+	// Manifests to restore, in the order they were originally applied
+	type layer struct {
+		manifest *domain.ProjectManifest
+		subpath  string
+	}
+
+	layers := []*layer{{manifest: project.Manifest}}
+	if !baseOnly {
+		for _, entry := range project.History {
+			if entry.Manifest == nil {
+				logger.Warnf("Package %q was applied without a build manifest; skipping", entry.Package)
+				continue
+			}
+
+			layers = append(layers, &layer{manifest: entry.Manifest, subpath: entry.Subpath})
+		}
+	}
+
+	fs := afero.NewBasePathFs(afero.NewOsFs(), dest)
+	files := 0
+	for _, layer := range layers {
+		dir := filepath.Join(dest, layer.subpath)
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return errors.Wrapf(err, "create directory %q", dir)
+		}
+
+		if runPlugins {
+			if err = runManifestPlugins(ctx, layer.manifest, "pre", dir); err != nil {
+				return err
+			}
+		}
+
+		written, err := writeManifestFiles(ctx, fs, layer.manifest, layer.subpath)
+		if err != nil {
+			return errors.Wrapf(err, "restore files of project %q", project.Name)
+		}
+		files += written
+
+		if runPlugins {
+			if err = runManifestPlugins(ctx, layer.manifest, "post", dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Infof("Successfully regenerated project %q into %q (%d files)", project.Name, dest, files)
+
+	return nil
+}
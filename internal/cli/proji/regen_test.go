@@ -0,0 +1,67 @@
+package proji
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestWriteManifestFiles(t *testing.T) {
+	t.Parallel()
+
+	readme := domain.NewManifestFile("docs/README.md", []byte("# proji"))
+	readme.Content = []byte("# proji")
+
+	manifest := &domain.ProjectManifest{
+		Files: []*domain.ManifestFile{
+			domain.NewManifestDir("cmd"),
+			readme,
+			domain.NewManifestFile("Makefile", nil),
+			domain.NewManifestFile("logo.png", []byte("\x89PNG")), // Content not recorded
+		},
+	}
+
+	cases := []struct {
+		name    string
+		subpath string
+		prefix  string
+	}{
+		{name: "project root", subpath: "", prefix: ""},
+		{name: "subpath", subpath: "services/billing", prefix: "services/billing/"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			written, err := writeManifestFiles(context.Background(), fs, manifest, tc.subpath)
+			if err != nil {
+				t.Fatalf("writeManifestFiles() error = %v", err)
+			}
+			if written != 2 {
+				t.Fatalf("writeManifestFiles() wrote %d files, want 2", written)
+			}
+
+			if exists, _ := afero.DirExists(fs, tc.prefix+"cmd"); !exists {
+				t.Fatalf("expected directory %q to exist", tc.prefix+"cmd")
+			}
+			if exists, _ := afero.Exists(fs, tc.prefix+"logo.png"); exists {
+				t.Fatalf("expected file %q without recorded content to be skipped", tc.prefix+"logo.png")
+			}
+			for path, want := range map[string]string{"docs/README.md": "# proji", "Makefile": ""} {
+				data, err := afero.ReadFile(fs, tc.prefix+path)
+				if err != nil {
+					t.Fatalf("failed to read file %q: %v", tc.prefix+path, err)
+				}
+				if string(data) != want {
+					t.Fatalf("expected content %q in %q, got %q", want, tc.prefix+path, data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/projects"
+)
+
+// relocation is a tracked project that was found at a new location.
+type relocation struct {
+	Project *domain.Project
+	OldPath string
+	NewPath string
+}
+
+func projectRelocateCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:                   "relocate [OPTIONS] [ROOT...]",
+		Aliases:               []string{"scan"},
+		Short:                 "Find projects that were moved or renamed and update their paths",
+		DisableFlagsInUseLine: true,
+
+		Long: `Searches for the marker files of projects whose path no longer exists and updates their paths. If no root
+directories are given, the scan roots of the config (projects.scan_roots) are searched.`,
+
+		Example: `  proji project relocate
+  proji project relocate --dry-run ~/code ~/archive`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return relocateCmd(cmd.Context(), args, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show which projects would be relocated without updating them")
+
+	return cmd
+}
+
+// writeProjectMarker writes the marker file of the project into its root directory. Projects that can't be marked
+// still work; they just can't be found again after being moved.
+func writeProjectMarker(ctx context.Context, project *domain.ProjectAdd) {
+	marker := &projects.Marker{ID: project.ID, Package: project.Package}
+	if err := projects.WriteMarker(afero.NewOsFs(), project.Path, marker); err != nil {
+		simplog.FromContext(ctx).Warnf("Failed to write project marker: %v", err)
+	}
+}
+
+// scanRoots returns the absolute paths of the given root directories. If no roots are given, the scan roots of the
+// config are used.
+func scanRoots(ctx context.Context, roots []string) ([]string, error) {
+	if len(roots) == 0 {
+		if conf := cli.SessionFromContext(ctx).Config; conf != nil {
+			roots = conf.Projects.ScanRoots
+		}
+	}
+
+	absRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		root = fsutil.ExpandPath(strings.TrimSpace(root))
+
+		absRoot, err := localPathToAbsPath(root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get absolute path to %q", root)
+		}
+
+		absRoots = append(absRoots, filepath.Clean(absRoot))
+	}
+
+	return absRoots, nil
+}
+
+// relocateProjects searches the roots for the markers of projects whose path no longer exists. Projects that were
+// found at exactly one new location get their path updated, unless dryRun is set. Projects that were found at multiple
+// locations are ambiguous and left untouched.
+func relocateProjects(ctx context.Context, roots []string, dryRun bool) ([]*relocation, error) {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return nil, errors.New("no project manager found")
+	}
+
+	logger.Debug("fetching project list")
+	projectList, err := prma.Fetch(ctx)
+	if errors.Is(err, database.ErrBucketNotFound) {
+		return nil, nil // No projects stored yet
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch projects")
+	}
+
+	tracked := make(map[string]struct{}, len(projectList))
+	missing := make([]*domain.Project, 0)
+	for idx := range projectList {
+		tracked[projectList[idx].Path] = struct{}{}
+		if projectList[idx].Archive == nil && !doesPathExist(projectList[idx].Path) {
+			missing = append(missing, &projectList[idx])
+		}
+	}
+	if len(missing) == 0 || len(roots) == 0 {
+		return nil, nil
+	}
+
+	depth := 0
+	if session.Config != nil {
+		depth = session.Config.Projects.ScanDepth
+	}
+
+	logger.Debugf("scanning %d root directories for project markers", len(roots))
+	found, err := projects.ScanMarkers(ctx, afero.NewOsFs(), roots, depth)
+	if err != nil {
+		return nil, errors.Wrap(err, "scan for project markers")
+	}
+
+	relocations := make([]*relocation, 0, len(missing))
+	for _, project := range missing {
+		// Copies of a project might live at paths that are tracked as different projects already
+		candidates := make([]string, 0, len(found[project.ID]))
+		for _, path := range found[project.ID] {
+			if _, exists := tracked[path]; !exists {
+				candidates = append(candidates, path)
+			}
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+		if len(candidates) > 1 {
+			logger.Warnf("Project %q was found at multiple locations, skipping: %s", project.Name,
+				strings.Join(candidates, ", "))
+			continue
+		}
+
+		relocations = append(relocations, &relocation{Project: project, OldPath: project.Path, NewPath: candidates[0]})
+		if dryRun {
+			continue
+		}
+
+		logger.Debugf("updating path of project %q to %q", project.ID, candidates[0])
+		err = prma.Update(ctx, &domain.ProjectUpdate{ID: project.ID, Path: candidates[0]})
+		if err != nil {
+			return relocations, errors.Wrapf(err, "update path of project %q", project.ID)
+		}
+	}
+
+	return relocations, nil
+}
+
+func renderRelocations(relocations []*relocation) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "ID", "Name", "Old Path", "New Path")
+
+	for idx, relocation := range relocations {
+		table.AddRow(idx+1, relocation.Project.ID, relocation.Project.Name, relocation.OldPath, relocation.NewPath)
+	}
+
+	return table.Render()
+}
+
+func relocateCmd(ctx context.Context, roots []string, dryRun bool) error {
+	logger := simplog.FromContext(ctx)
+
+	roots, err := scanRoots(ctx, roots)
+	if err != nil {
+		return err
+	}
+	if len(roots) == 0 {
+		return errors.New("no root directories given and no scan roots configured (projects.scan_roots)")
+	}
+
+	relocations, err := relocateProjects(ctx, roots, dryRun)
+	if err != nil {
+		return errors.Wrap(err, "relocate projects")
+	}
+	if len(relocations) == 0 {
+		logger.Info("No moved projects found")
+		return nil
+	}
+
+	if err = renderRelocations(relocations); err != nil {
+		return errors.Wrap(err, "render relocations")
+	}
+
+	if !dryRun {
+		logger.Infof("Successfully relocated %d projects", len(relocations))
+	}
+
+	return nil
+}
@@ -2,16 +2,30 @@ package proji
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/nikoksr/simplog"
 	"github.com/spf13/cobra"
 
 	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/fsutil"
 )
 
+// removeOptions controls what happens to the directories of removed projects.
+type removeOptions struct {
+	Purge      bool   // Delete the project directory as well
+	ArchiveDir string // Move the project directory into this directory instead of deleting it; implies Purge
+	Force      bool   // Don't ask for confirmation
+}
+
 func projectRemoveCommand() *cobra.Command {
-	var forceRemoveProjects bool
+	opts := &removeOptions{}
 
 	cmd := &cobra.Command{
 		Use:                   "rm [OPTIONS] ID [ID...]",
@@ -19,17 +33,189 @@ func projectRemoveCommand() *cobra.Command {
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
 
+		Example: `  proji rm cf1l3q4bvs0e0m0ibmcg
+  proji rm --purge cf1l3q4bvs0e0m0ibmcg
+  proji rm --archive-dir ~/archive --force cf1l3q4bvs0e0m0ibmcg`,
+
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return removeProjects(cmd.Context(), args...)
+			if opts.ArchiveDir != "" {
+				opts.Purge = true
+			}
+
+			return removeProjects(cmd.Context(), opts, args...)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&forceRemoveProjects, "force", "f", false, "Don't ask for confirmation")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Don't ask for confirmation")
+	cmd.Flags().BoolVar(&opts.Purge, "purge", false, "Delete the project directory as well")
+	cmd.Flags().StringVar(&opts.ArchiveDir, "archive-dir", "",
+		"Move the project directory into this directory instead of deleting it; implies --purge")
 
 	return cmd
 }
 
-func removeProjects(ctx context.Context, ids ...string) error {
+// promptConfirm asks the user a yes/no question. Anything but an explicit yes counts as no.
+func promptConfirm(question string) (bool, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+
+	if _, err := fmt.Printf("   > %s [y/N] ", question); err != nil {
+		return false, errors.Wrap(err, "prompt confirmation")
+	}
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil {
+		return false, errors.Wrap(err, "read confirmation")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// checkPurgeable makes sure that the path is safe to delete; proji never deletes the filesystem root or the user's
+// home directory, no matter what the database says.
+func checkPurgeable(path string) error {
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return errors.Newf("path %q is not absolute", path)
+	}
+	if path == filepath.Dir(path) {
+		return errors.Newf("refusing to delete filesystem root %q", path)
+	}
+	if home, err := os.UserHomeDir(); err == nil && path == filepath.Clean(home) {
+		return errors.Newf("refusing to delete home directory %q", path)
+	}
+
+	return nil
+}
+
+// archivePath returns the path that the project directory gets moved to inside of archiveDir. A timestamp is appended
+// if the directory's name is already taken.
+func archivePath(archiveDir, path string) string {
+	target := filepath.Join(archiveDir, filepath.Base(path))
+	if _, err := os.Lstat(target); err == nil {
+		target += "-" + time.Now().Format("20060102150405")
+	}
+
+	return target
+}
+
+// purgeProject deletes or archives the directory of the project. It returns false if the user declined.
+func purgeProject(ctx context.Context, project *domain.Project, opts *removeOptions) (bool, error) {
+	logger := simplog.FromContext(ctx)
+
+	// Archived projects have no directory; their archive is what gets purged
+	if project.Archive != nil {
+		return purgeArchive(ctx, project, opts)
+	}
+
+	info, err := os.Stat(project.Path)
+	if os.IsNotExist(err) {
+		logger.Debugf("project directory %q does not exist; nothing to purge", project.Path)
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "stat %q", project.Path)
+	}
+	if !info.IsDir() {
+		return false, errors.Newf("project path %q is not a directory", project.Path)
+	}
+	if err = checkPurgeable(project.Path); err != nil {
+		return false, err
+	}
+
+	size, err := fsutil.DirSize(project.Path)
+	if err != nil {
+		return false, errors.Wrapf(err, "get size of %q", project.Path)
+	}
+
+	question := fmt.Sprintf("Delete project %q at %q (%s)?", project.Name, project.Path, fsutil.FormatSize(size))
+	if opts.ArchiveDir != "" {
+		question = fmt.Sprintf("Move project %q at %q (%s) to %q?", project.Name, project.Path,
+			fsutil.FormatSize(size), opts.ArchiveDir)
+	}
+
+	if !opts.Force {
+		confirmed, err := promptConfirm(question)
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			return false, nil
+		}
+	}
+
+	if opts.ArchiveDir != "" {
+		target := archivePath(opts.ArchiveDir, project.Path)
+
+		logger.Debugf("moving project directory %q to %q", project.Path, target)
+		if err = fsutil.Move(project.Path, target); err != nil {
+			return false, errors.Wrapf(err, "archive project directory %q", project.Path)
+		}
+
+		logger.Infof("Archived project directory %q to %q", project.Path, target)
+
+		return true, nil
+	}
+
+	logger.Debugf("deleting project directory %q", project.Path)
+	if err = os.RemoveAll(project.Path); err != nil {
+		return false, errors.Wrapf(err, "delete project directory %q", project.Path)
+	}
+
+	return true, nil
+}
+
+// purgeArchive deletes the archive of an archived project or moves it into the archive directory. It returns false if
+// the user declined.
+func purgeArchive(ctx context.Context, project *domain.Project, opts *removeOptions) (bool, error) {
+	logger := simplog.FromContext(ctx)
+
+	if _, err := os.Stat(project.Archive.Path); os.IsNotExist(err) {
+		logger.Debugf("project archive %q does not exist; nothing to purge", project.Archive.Path)
+		return true, nil
+	}
+
+	question := fmt.Sprintf("Delete archive of project %q at %q (%s)?", project.Name, project.Archive.Path,
+		fsutil.FormatSize(project.Archive.Size))
+	if opts.ArchiveDir != "" {
+		question = fmt.Sprintf("Move archive of project %q at %q (%s) to %q?", project.Name, project.Archive.Path,
+			fsutil.FormatSize(project.Archive.Size), opts.ArchiveDir)
+	}
+
+	if !opts.Force {
+		confirmed, err := promptConfirm(question)
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			return false, nil
+		}
+	}
+
+	if opts.ArchiveDir != "" {
+		target := archivePath(opts.ArchiveDir, project.Archive.Path)
+		if err := fsutil.Move(project.Archive.Path, target); err != nil {
+			return false, errors.Wrapf(err, "move project archive %q", project.Archive.Path)
+		}
+
+		logger.Infof("Moved project archive %q to %q", project.Archive.Path, target)
+
+		return true, nil
+	}
+
+	if err := os.Remove(project.Archive.Path); err != nil {
+		return false, errors.Wrapf(err, "delete project archive %q", project.Archive.Path)
+	}
+
+	return true, nil
+}
+
+func removeProjects(ctx context.Context, opts *removeOptions, ids ...string) error {
 	logger := simplog.FromContext(ctx)
 
 	// Get project manager from session
@@ -39,13 +225,40 @@ func removeProjects(ctx context.Context, ids ...string) error {
 		return errors.New("no project manager found")
 	}
 
+	if opts.ArchiveDir != "" {
+		archiveDir, err := localPathToAbsPath(strings.TrimSpace(opts.ArchiveDir))
+		if err != nil {
+			return errors.Wrapf(err, "get absolute path to %q", opts.ArchiveDir)
+		}
+		opts.ArchiveDir = archiveDir
+	}
+
 	// Removing projects
 	logger.Debugf("removing %d projects", len(ids))
 	for _, id := range ids {
+		if opts.Purge {
+			project, err := prma.GetByID(ctx, id)
+			if err != nil {
+				logger.Warnf("Failed to get project %q: %v", id, err)
+				continue
+			}
+
+			purged, err := purgeProject(ctx, &project, opts)
+			if err != nil {
+				logger.Warnf("Failed to purge project %q: %v", id, err)
+				continue
+			}
+			if !purged {
+				logger.Infof("Keeping project %q", id)
+				continue
+			}
+		}
+
 		logger.Debugf("removing project %q", id)
 		if err := prma.Remove(ctx, id); err != nil {
 			logger.Warnf("Failed to remove project %q: %v", id, err)
 		} else {
+			forgetWorkspaceMember(ctx, id)
 			logger.Infof("Successfully removed project %q", id)
 		}
 	}
@@ -95,11 +95,18 @@ func rootCommand() *cobra.Command {
 				return errors.Wrap(err, "setup project manager")
 			}
 
+			// Create workspace manager
+			wsma, err := manager.NewWorkspaceManager(ctx, db)
+			if err != nil {
+				return errors.Wrap(err, "setup workspace manager")
+			}
+
 			// Create a cli.Session and bind it to the command context
 			session := cli.NewSessionWithMode(debug).
 				WithConfig(conf).
 				WithPackageManager(pama).
-				WithProjectManager(prma)
+				WithProjectManager(prma).
+				WithWorkspaceManager(wsma)
 
 			ctx = cli.WithSession(ctx, session)
 
@@ -124,9 +131,17 @@ func rootCommand() *cobra.Command {
 
 		// Projects
 		projectNewCommand(),
+		projectAddCommand(),
+		projectRegenCommand(),
 		projectRemoveCommand(),
 		projectCleanCommand(),
 		projectListCommand(),
+		projectFindCommand(),
+		projectCdCommand(),
+		projectStatusCommand(),
+		projectOutdatedCommand(),
+		projectCommand(),
+		workspaceCommand(),
 
 		// Packages
 		pkg.NewCommand(),
@@ -135,6 +150,7 @@ func rootCommand() *cobra.Command {
 		server.NewCommand(),
 
 		// Misc
+		shellInitCommand(),
 		version.NewCommand(buildinfo.AppVersion),
 	)
 
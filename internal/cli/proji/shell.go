@@ -0,0 +1,72 @@
+package proji
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+// shellScripts hold the shell integration per shell. They wrap the proji binary in a shell function, so that
+// 'proji cd' changes the working directory of the shell itself.
+var shellScripts = map[string]string{
+	"bash": posixShellScript("bash", "~/.bashrc"),
+	"zsh":  posixShellScript("zsh", "~/.zshrc"),
+	"fish": `# proji shell integration. Add the following line to ~/.config/fish/config.fish:
+#
+#   proji shell-init fish | source
+#
+function proji --wraps proji --description 'proji with cd support'
+    if test (count $argv) -gt 0; and test "$argv[1]" = cd
+        set -l __proji_dir (command proji $argv); or return $status
+        test -n "$__proji_dir"; and builtin cd -- $__proji_dir
+    else
+        command proji $argv
+    end
+end
+`,
+}
+
+func posixShellScript(shell, rcFile string) string {
+	return fmt.Sprintf(`# proji shell integration. Add the following line to %s:
+#
+#   eval "$(proji shell-init %s)"
+#
+proji() {
+    if [ "$1" = "cd" ]; then
+        local __proji_dir
+        __proji_dir="$(command proji "$@")" || return $?
+        [ -n "$__proji_dir" ] && builtin cd -- "$__proji_dir"
+    else
+        command proji "$@"
+    fi
+}
+`, rcFile, shell)
+}
+
+func shellInitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "shell-init SHELL",
+		Short:                 "Print the shell integration for bash, zsh or fish",
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		DisableFlagsInUseLine: true,
+
+		Example: `  eval "$(proji shell-init bash)"
+  eval "$(proji shell-init zsh)"
+  proji shell-init fish | source`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			script, ok := shellScripts[args[0]]
+			if !ok {
+				return errors.Newf("unsupported shell %q; supported shells are bash, zsh and fish", args[0])
+			}
+
+			_, err := fmt.Fprint(cmd.OutOrStdout(), script)
+
+			return err
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,291 @@
+package proji
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/fsutil"
+)
+
+const defaultStaleDays = 90
+
+type statsOptions struct {
+	Exclude    string
+	StaleDays  int
+	OnlyStale  bool
+	SortBySize bool
+	JSON       bool
+}
+
+// projectStats are the disk usage and activity statistics of a tracked project.
+type projectStats struct {
+	ProjectID   string        `json:"project_id"`
+	ProjectName string        `json:"project_name"`
+	ProjectPath string        `json:"project_path"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Missing     bool          `json:"missing,omitempty"` // The project path does not exist
+	Archived    bool          `json:"archived,omitempty"`
+	Stale       bool          `json:"stale"` // Nothing was modified within the stale period
+	Usage       *fsutil.Usage `json:"usage,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// lastActivity returns the last modification time of the project or, if nothing was modified yet, its creation time.
+func (s *projectStats) lastActivity() time.Time {
+	if s.Usage != nil && s.Usage.LastModified.After(s.CreatedAt) {
+		return s.Usage.LastModified
+	}
+
+	return s.CreatedAt
+}
+
+func projectStatsCommand() *cobra.Command {
+	var options statsOptions
+
+	cmd := &cobra.Command{
+		Use:                   "stats [OPTIONS] [ID...]",
+		Short:                 "Show disk usage and activity of tracked projects",
+		DisableFlagsInUseLine: true,
+
+		Long: `Walks the tracked projects and reports their size on disk, the number of files, when they were last
+modified and how long ago they were created. Projects without any modification within the stale period are marked
+as stale. Paths that match the exclude pattern, by default import.exclude of the config, are not counted.`,
+
+		Example: `  proji project stats
+  proji project stats --stale --stale-after 180
+  proji project stats --sort-size --exclude '(^|/)(node_modules|vendor)$'
+  proji project stats --json cf1l3q4bvs0e0m0ibmcg`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showProjectStats(cmd.Context(), args, &options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Exclude, "exclude", "e", "", "Regex pattern to exclude paths from the walk")
+	cmd.Flags().IntVar(&options.StaleDays, "stale-after", defaultStaleDays,
+		"Number of days without modification after which a project counts as stale")
+	cmd.Flags().BoolVar(&options.OnlyStale, "stale", false, "Only show stale projects")
+	cmd.Flags().BoolVar(&options.SortBySize, "sort-size", false, "Sort projects by size, largest first")
+	cmd.Flags().BoolVar(&options.JSON, "json", false, "Print the statistics as JSON")
+
+	return cmd
+}
+
+// collectProjectStats walks the projects concurrently and collects their statistics. Projects that can't be walked
+// get the error attached instead of failing the whole collection.
+func collectProjectStats(ctx context.Context, projectList []domain.Project, exclude *regexp.Regexp,
+	staleAfter time.Duration,
+) ([]*projectStats, error) {
+	stats := make([]*projectStats, len(projectList))
+	now := time.Now()
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(runtime.NumCPU())
+
+	for idx := range projectList {
+		project := &projectList[idx]
+		projectStat := &projectStats{
+			ProjectID:   project.ID,
+			ProjectName: project.Name,
+			ProjectPath: project.Path,
+			CreatedAt:   project.CreatedAt,
+		}
+		stats[idx] = projectStat
+
+		if project.Archive != nil {
+			projectStat.Archived = true
+			continue
+		}
+		if !doesPathExist(project.Path) {
+			projectStat.Missing = true
+			continue
+		}
+
+		group.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			usage, err := fsutil.DirUsage(project.Path, exclude)
+			if err != nil {
+				projectStat.Error = err.Error()
+				return nil
+			}
+
+			projectStat.Usage = usage
+			projectStat.Stale = now.Sub(projectStat.lastActivity()) > staleAfter
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// formatAge formats the duration in days, months or years, e.g. 5d, 3mo or 2y.
+func formatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%dd", days)
+	case days < 730:
+		return fmt.Sprintf("%dmo", days/30)
+	default:
+		return fmt.Sprintf("%dy", days/365)
+	}
+}
+
+func renderProjectStats(stats []*projectStats) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "ID", "Name", "Size", "Files", "Last modified", "Age", "State")
+
+	now := time.Now()
+	var totalSize int64
+	var totalFiles int
+
+	for idx, projectStat := range stats {
+		size, files, lastModified, state := "", "", "", "active"
+
+		switch {
+		case projectStat.Error != "":
+			state = "error: " + projectStat.Error
+		case projectStat.Archived:
+			state = "archived"
+		case projectStat.Missing:
+			state = "missing"
+		case projectStat.Stale:
+			state = "stale"
+		}
+
+		if projectStat.Usage != nil {
+			size, files = fsutil.FormatSize(projectStat.Usage.Size), fmt.Sprint(projectStat.Usage.Files)
+			lastModified = projectStat.lastActivity().Local().Format("2006-01-02")
+			totalSize += projectStat.Usage.Size
+			totalFiles += projectStat.Usage.Files
+		}
+
+		table.AddRow(idx+1, projectStat.ProjectID, projectStat.ProjectName, size, files, lastModified,
+			formatAge(now.Sub(projectStat.CreatedAt)), state)
+	}
+
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Printf("\nTotal: %s in %d files\n", fsutil.FormatSize(totalSize), totalFiles)
+
+	return err
+}
+
+func showProjectStats(ctx context.Context, ids []string, options *statsOptions) error {
+	logger := simplog.FromContext(ctx)
+
+	if options.StaleDays < 0 {
+		return errors.Newf("stale period must not be negative, got %d days", options.StaleDays)
+	}
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return errors.New("no project manager available")
+	}
+
+	// Compile regex pattern for excluding paths. Value from flag has priority over value from config.
+	exclude := options.Exclude
+	if exclude == "" && session.Config != nil {
+		exclude = session.Config.Import.Exclude
+	}
+
+	reExclude, err := regexp.Compile(exclude)
+	if err != nil {
+		return errors.Wrap(err, "compile exclude regexp")
+	}
+
+	var projectList []domain.Project
+	if len(ids) > 0 {
+		for _, id := range ids {
+			project, err := prma.GetByID(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "get project %q", id)
+			}
+
+			projectList = append(projectList, project)
+		}
+	} else {
+		logger.Debug("fetching project list")
+		projectList, err = prma.Fetch(ctx)
+		if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+			return errors.Wrap(err, "fetch projects")
+		}
+	}
+
+	logger.Debugf("collecting statistics of %d projects", len(projectList))
+	staleAfter := time.Duration(options.StaleDays) * 24 * time.Hour
+	stats, err := collectProjectStats(ctx, projectList, reExclude, staleAfter)
+	if err != nil {
+		return errors.Wrap(err, "collect project statistics")
+	}
+
+	if options.OnlyStale {
+		filtered := stats[:0]
+		for _, projectStat := range stats {
+			if projectStat.Stale {
+				filtered = append(filtered, projectStat)
+			}
+		}
+		stats = filtered
+	}
+
+	if options.SortBySize {
+		sort.SliceStable(stats, func(i, j int) bool {
+			var sizeI, sizeJ int64
+			if stats[i].Usage != nil {
+				sizeI = stats[i].Usage.Size
+			}
+			if stats[j].Usage != nil {
+				sizeJ = stats[j].Usage.Size
+			}
+
+			return sizeI > sizeJ
+		})
+	}
+
+	if options.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		logger.Info("No projects found")
+		return nil
+	}
+
+	return renderProjectStats(stats)
+}
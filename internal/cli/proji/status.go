@@ -0,0 +1,231 @@
+package proji
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/gitstatus"
+)
+
+// projectStatus is the git status of a tracked project.
+type projectStatus struct {
+	ProjectID   string            `json:"project_id"`
+	ProjectName string            `json:"project_name"`
+	ProjectPath string            `json:"project_path"`
+	Missing     bool              `json:"missing,omitempty"` // The project path does not exist
+	Archived    bool              `json:"archived,omitempty"`
+	Git         *gitstatus.Status `json:"git,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// needsAttention reports whether the project has uncommitted changes, unpushed commits or an error.
+func (s *projectStatus) needsAttention() bool {
+	if s.Error != "" || s.Missing {
+		return true
+	}
+	if s.Git == nil || !s.Git.IsRepo {
+		return false
+	}
+
+	return s.Git.Dirty() || s.Git.Unpushed > 0 || s.Git.Behind > 0
+}
+
+// state summarizes the status for humans.
+func (s *projectStatus) state() string {
+	switch {
+	case s.Error != "":
+		return "error: " + s.Error
+	case s.Archived:
+		return "archived"
+	case s.Missing:
+		return "missing"
+	case s.Git == nil || !s.Git.IsRepo:
+		return "no repository"
+	case s.Git.Dirty():
+		return "dirty (" + strconv.Itoa(s.Git.Changes) + ")"
+	default:
+		return "clean"
+	}
+}
+
+func projectStatusCommand() *cobra.Command {
+	var attention, asJSON bool
+	var workspace string
+
+	cmd := &cobra.Command{
+		Use:                   "status [OPTIONS] [ID...]",
+		Short:                 "Show the git status of tracked projects",
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji status
+  proji status --attention
+  proji status --json cf1l3q4bvs0e0m0ibmcg
+  proji status --workspace shop`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if workspace != "" && len(args) > 0 {
+				return errors.New("--workspace can't be combined with project IDs")
+			}
+
+			return showProjectStatus(cmd.Context(), args, workspace, attention, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&attention, "attention", "a", false,
+		"Only show projects with uncommitted changes, unpushed commits or errors")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the status as JSON")
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Only show the projects of this workspace")
+
+	return cmd
+}
+
+// inspectProjects collects the git status of the projects concurrently.
+func inspectProjects(ctx context.Context, projectList []domain.Project) ([]*projectStatus, error) {
+	statuses := make([]*projectStatus, len(projectList))
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(runtime.NumCPU())
+
+	for idx := range projectList {
+		project := &projectList[idx]
+		status := &projectStatus{ProjectID: project.ID, ProjectName: project.Name, ProjectPath: project.Path}
+		statuses[idx] = status
+
+		if project.Archive != nil {
+			status.Archived = true
+			continue
+		}
+		if !doesPathExist(project.Path) {
+			status.Missing = true
+			continue
+		}
+
+		group.Go(func() error {
+			gitStatus, err := gitstatus.Inspect(ctx, project.Path)
+			if errors.Is(err, gitstatus.ErrGitNotFound) {
+				return err
+			}
+			if err != nil {
+				status.Error = err.Error()
+				return nil
+			}
+
+			status.Git = gitStatus
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func renderProjectStatus(statuses []*projectStatus) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "ID", "Name", "Branch", "State", "Unpushed", "Behind", "Last commit")
+
+	for idx, status := range statuses {
+		branch, unpushed, behind, lastCommit := "", "", "", ""
+		if status.Git != nil && status.Git.IsRepo {
+			branch = status.Git.Branch
+			if branch == "" {
+				branch = "(detached)"
+			}
+			unpushed, behind = strconv.Itoa(status.Git.Unpushed), strconv.Itoa(status.Git.Behind)
+			if status.Git.LastCommit != nil {
+				lastCommit = status.Git.LastCommit.Local().Format("2006-01-02 15:04")
+			}
+		}
+
+		table.AddRow(idx+1, status.ProjectID, status.ProjectName, branch, status.state(), unpushed, behind, lastCommit)
+	}
+
+	return table.Render()
+}
+
+func showProjectStatus(ctx context.Context, ids []string, workspaceName string, attention, asJSON bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get project manager from session
+	logger.Debug("getting project manager from cli session")
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager available")
+	}
+
+	var projectList []domain.Project
+	if workspaceName != "" {
+		workspace, err := getWorkspace(ctx, workspaceName)
+		if err != nil {
+			return err
+		}
+
+		if projectList, err = workspaceProjects(ctx, workspace); err != nil {
+			return errors.Wrapf(err, "load projects of workspace %q", workspace.Name)
+		}
+	} else if len(ids) > 0 {
+		for _, id := range ids {
+			project, err := prma.GetByID(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "get project %q", id)
+			}
+
+			projectList = append(projectList, project)
+		}
+	} else {
+		var err error
+
+		logger.Debug("fetching project list")
+		projectList, err = prma.Fetch(ctx)
+		if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+			return errors.Wrap(err, "fetch projects")
+		}
+	}
+
+	logger.Debugf("inspecting %d projects", len(projectList))
+	statuses, err := inspectProjects(ctx, projectList)
+	if err != nil {
+		return errors.Wrap(err, "inspect projects")
+	}
+
+	if attention {
+		filtered := statuses[:0]
+		for _, status := range statuses {
+			if status.needsAttention() {
+				filtered = append(filtered, status)
+			}
+		}
+		statuses = filtered
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		logger.Info("No projects need attention")
+		return nil
+	}
+
+	return renderProjectStatus(statuses)
+}
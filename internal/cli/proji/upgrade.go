@@ -0,0 +1,446 @@
+package proji
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/diff"
+	"github.com/nikoksr/proji/pkg/packages"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+type (
+	// upgradeStatus describes what happened to a path during an upgrade.
+	upgradeStatus string
+
+	// upgradeChange is a single change of an upgrade.
+	upgradeChange struct {
+		Status upgradeStatus
+		Path   string
+		Note   string
+	}
+)
+
+const (
+	upgradeCreated   upgradeStatus = "created"   // New in the package
+	upgradeUpdated   upgradeStatus = "updated"   // Unchanged by the user; replaced by the new version
+	upgradeMerged    upgradeStatus = "merged"    // Changed by the user and the package; merged cleanly
+	upgradeConflict  upgradeStatus = "conflict"  // Changed differently by the user and the package
+	upgradePatched   upgradeStatus = "patched"   // Changed differently; the package's changes were written as patch
+	upgradeUnchanged upgradeStatus = "unchanged" // Already up-to-date
+	upgradeKept      upgradeStatus = "kept"      // Left untouched; see note
+)
+
+// patchSuffix is appended to the path of a file to get the path of its upgrade patch.
+const patchSuffix = ".proji.patch"
+
+func projectUpgradeCommand() *cobra.Command {
+	var dryRun, patch bool
+
+	cmd := &cobra.Command{
+		Use:                   "upgrade [OPTIONS] [ID]",
+		Short:                 "Upgrade a project to the latest revision of its packages",
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji project upgrade
+  proji project upgrade --dry-run cf1l3q4bvs0e0m0ibmcg
+  proji project upgrade --patch cf1l3q4bvs0e0m0ibmcg`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectID := ""
+			if len(args) > 0 {
+				projectID = args[0]
+			}
+
+			return upgradeProject(cmd.Context(), projectID, dryRun, patch)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without touching the filesystem")
+	cmd.Flags().BoolVar(&patch, "patch", false,
+		"Write a patch next to files that were changed by the user instead of inserting conflict markers")
+
+	return cmd
+}
+
+// renderPackage renders the directory tree of the given package into memory. The returned builder holds the generated
+// files. Missing template values are requested through missingKeyFn.
+func renderPackage(
+	ctx context.Context, _package *domain.Package, templatesDir string, answers map[string]string,
+	missingKeyFn templates.MissingKeyFn,
+) (*builder.Builder, error) {
+	tmpl := templates.NewEngine("", "")
+	tmpl.MissingKeyFn = missingKeyFn
+	tmpl.SetValues(answers)
+
+	build := builder.New(afero.NewMemMapFs(), templatesDir, tmpl)
+	if _package.DirTree == nil {
+		return build, nil
+	}
+
+	if err := build.CreateEntries(ctx, _package.DirTree.Entries); err != nil {
+		return nil, err
+	}
+
+	return build, nil
+}
+
+// isBinary reports whether the content looks like binary data. Binary files are never merged.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// upgradeFile brings the file at next.Path up-to-date with its newly generated version, which has the given content.
+// base is the version that was generated originally; it's nil if the file is new in the package.
+func upgradeFile(
+	fs afero.Fs, base, next *domain.ManifestFile, nextContent []byte, nextLabel string, patch, dryRun bool,
+) (*upgradeChange, error) {
+	path := filepath.FromSlash(next.Path)
+	change := &upgradeChange{Path: next.Path}
+
+	current, err := afero.ReadFile(fs, path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrapf(err, "read file %q", path)
+	}
+
+	// A file that was not generated before gets compared against an empty base
+	var baseContent []byte
+	if base != nil {
+		baseContent = base.Content
+	}
+
+	var content []byte
+	switch {
+	case !exists && base != nil:
+		change.Status, change.Note = upgradeKept, "deleted by the user"
+		return change, nil
+	case !exists:
+		change.Status, content = upgradeCreated, nextContent
+	case bytes.Equal(current, nextContent):
+		change.Status = upgradeUnchanged
+		return change, nil
+	case base != nil && base.Matches(current):
+		change.Status, content = upgradeUpdated, nextContent
+	case base != nil && base.Matches(nextContent):
+		change.Status, change.Note = upgradeKept, "changed by the user"
+		return change, nil
+	case isBinary(current) || isBinary(nextContent):
+		change.Status, change.Note = upgradeKept, "binary file changed by the user and the package"
+		return change, nil
+	case base != nil && !base.HasContent():
+		// Only the hash of the original version is known; there's nothing to merge against
+		change.Status, change.Note = upgradeKept, "changed by the user and the package; no merge base recorded"
+		return change, nil
+	case patch:
+		// Leave the file alone and write the package's changes as patch next to it
+		change.Status, change.Note = upgradePatched, next.Path+patchSuffix
+		path += patchSuffix
+		content = []byte(diff.Unified("a/"+next.Path, "b/"+next.Path, string(baseContent), string(nextContent), 3))
+	default:
+		// Three-way merge between the originally generated file, the current file and the new version
+		merged := diff.Merge(string(baseContent), string(current), string(nextContent), "current", nextLabel)
+		change.Status, content = upgradeMerged, []byte(merged.Text)
+		if merged.Conflicts > 0 {
+			change.Status, change.Note = upgradeConflict, strconv.Itoa(merged.Conflicts)+" conflict(s)"
+		}
+	}
+
+	if dryRun {
+		return change, nil
+	}
+
+	if err = fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrapf(err, "create directory %q", filepath.Dir(path))
+	}
+	if err = afero.WriteFile(fs, path, content, 0o644); err != nil {
+		return nil, errors.Wrapf(err, "write file %q", path)
+	}
+
+	return change, nil
+}
+
+// applied reports whether the new version of the file was written, so that the file is up-to-date with the package.
+func (c *upgradeChange) applied() bool {
+	return c.Status != upgradeKept && c.Status != upgradePatched
+}
+
+func renderUpgradeChanges(changes []*upgradeChange) error {
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Status", "Path", "Note")
+
+	for idx, change := range changes {
+		table.AddRow(idx+1, change.Status, change.Path, change.Note)
+	}
+
+	return table.Render()
+}
+
+// upgradeFiles brings the files in fs up-to-date with the files that were rendered into the given builder. manifest
+// describes the files as they were generated originally. It returns the changes and the files that serve as base for
+// future upgrades: the newly generated ones, except for files whose new version was not applied. Those keep their old
+// base, so that the next upgrade still sees the package's changes.
+func upgradeFiles(
+	fs afero.Fs, manifest *domain.ProjectManifest, rendered *builder.Builder, nextLabel string, patch, dryRun bool,
+) ([]*upgradeChange, []*domain.ManifestFile, error) {
+	// Compare every generated file with the original and the current version
+	base := make(map[string]*domain.ManifestFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		base[file.Path] = file
+	}
+
+	generated := make(map[string]struct{}, len(rendered.Files))
+	changes := make([]*upgradeChange, 0, len(rendered.Files))
+	files := make([]*domain.ManifestFile, 0, len(rendered.Files))
+
+	for _, file := range rendered.Files {
+		generated[file.Path] = struct{}{}
+
+		if file.IsDir {
+			files = append(files, file)
+
+			exists, err := afero.DirExists(fs, filepath.FromSlash(file.Path))
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "check if directory %q exists", file.Path)
+			}
+			if exists {
+				continue
+			}
+
+			if !dryRun {
+				if err = fs.MkdirAll(filepath.FromSlash(file.Path), 0o755); err != nil {
+					return nil, nil, errors.Wrapf(err, "create directory %q", file.Path)
+				}
+			}
+
+			changes = append(changes, &upgradeChange{Status: upgradeCreated, Path: file.Path + "/"})
+			continue
+		}
+
+		content, err := afero.ReadFile(rendered.FS, filepath.FromSlash(file.Path))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "read generated file %q", file.Path)
+		}
+
+		change, err := upgradeFile(fs, base[file.Path], file, content, nextLabel, patch, dryRun)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "upgrade file %q", file.Path)
+		}
+
+		switch {
+		case change.applied():
+			files = append(files, file)
+		case base[file.Path] != nil:
+			files = append(files, base[file.Path])
+		}
+
+		changes = append(changes, change)
+	}
+
+	// Files that are no longer part of the package are never removed
+	for _, file := range manifest.Files {
+		if _, exists := generated[file.Path]; !exists && !file.IsDir {
+			changes = append(changes, &upgradeChange{Status: upgradeKept, Path: file.Path, Note: "removed from package"})
+		}
+	}
+
+	return changes, files, nil
+}
+
+// upgradeTree upgrades the files that were generated from the packages of the given manifest. The files are located
+// below subpath of the project; the project's root for the packages it was built from, a subdirectory for packages
+// that were applied later on. It returns the changes, the manifest that serves as base for future upgrades and the
+// template values that were used.
+func upgradeTree(
+	ctx context.Context, project *domain.Project, manifest *domain.ProjectManifest, subpath string, dryRun, patch bool,
+) ([]*upgradeChange, *domain.ProjectManifest, map[string]string, error) {
+	logger := simplog.FromContext(ctx)
+	session := cli.SessionFromContext(ctx)
+
+	// Load the latest revisions of the packages that the tree was built from
+	labels := make([]string, 0, len(manifest.Packages))
+	for _, _package := range manifest.Packages {
+		labels = append(labels, _package.Label)
+	}
+
+	layers, err := loadPackages(ctx, session.PackageManager, labels...)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "load packages")
+	}
+
+	_package, err := packages.Compose(layers...)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "compose packages")
+	}
+
+	// Render the packages with the answers that were given originally; the user only gets asked for values of newly
+	// introduced template variables. Dry-runs never prompt.
+	answers := make(map[string]string, len(manifest.Answers)+len(project.Answers))
+	for key, value := range manifest.Answers {
+		answers[key] = value
+	}
+	for key, value := range project.Answers {
+		answers[key] = value
+	}
+
+	missingKeyFn := missingTemplateKeyFn
+	if dryRun {
+		missingKeyFn = newKeyRecorder().missingKeyFn
+	}
+
+	logger.Debugf("rendering package %q", _package.Label)
+	rendered, err := renderPackage(ctx, _package, session.Config.TemplatesDir(), answers, missingKeyFn)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "render package %q", _package.Label)
+	}
+
+	nextLabel := _package.Label
+	if len(layers) == 1 {
+		nextLabel += "@" + strconv.Itoa(layers[0].Revision)
+	}
+
+	next := newManifest(layers)
+	next.Answers = rendered.Engine.Values
+	next.Plugins = manifest.Plugins
+
+	fs := afero.NewBasePathFs(afero.NewOsFs(), filepath.Join(project.Path, filepath.FromSlash(subpath)))
+	changes, files, err := upgradeFiles(fs, manifest, rendered, nextLabel, patch, dryRun)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	next.Files = files
+
+	// Paths of the changes are shown relative to the project's root
+	if subpath != "" {
+		for _, change := range changes {
+			change.Path = path.Join(subpath, change.Path)
+			if change.Status == upgradePatched {
+				change.Note = path.Join(subpath, change.Note)
+			}
+		}
+	}
+
+	return changes, next, rendered.Engine.Values, nil
+}
+
+func upgradeProject(ctx context.Context, projectID string, dryRun, patch bool) error {
+	logger := simplog.FromContext(ctx)
+
+	// Get managers from session
+	logger.Debug("getting project and package manager from cli session")
+	session := cli.SessionFromContext(ctx)
+	prma := session.ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+	if session.PackageManager == nil {
+		return errors.New("no package manager found")
+	}
+
+	// Find the project that should be upgraded
+	project, err := loadTargetProject(ctx, prma, projectID)
+	if err != nil {
+		return errors.Wrap(err, "load project")
+	}
+
+	hasManifest := project.Manifest != nil && len(project.Manifest.Packages) > 0
+	var changes []*upgradeChange
+	var manifest *domain.ProjectManifest
+	answers := make(map[string]string, len(project.Answers))
+	for key, value := range project.Answers {
+		answers[key] = value
+	}
+
+	if hasManifest {
+		var values map[string]string
+		changes, manifest, values, err = upgradeTree(ctx, project, project.Manifest, "", dryRun, patch)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range values {
+			answers[key] = value
+		}
+	}
+
+	// Packages that were applied to the project later on, e.g. by 'proji add', are upgraded as well
+	history := make([]*domain.ProjectHistoryEntry, 0, len(project.History))
+	for _, applied := range project.History {
+		if applied == nil || applied.Manifest == nil || len(applied.Manifest.Packages) == 0 {
+			history = append(history, applied)
+			continue
+		}
+		hasManifest = true
+
+		logger.Debugf("upgrading package %q applied to %q", applied.Package, applied.Subpath)
+		appliedChanges, appliedManifest, values, err := upgradeTree(ctx, project, applied.Manifest, applied.Subpath,
+			dryRun, patch)
+		if err != nil {
+			return errors.Wrapf(err, "upgrade package %q applied to %q", applied.Package, applied.Subpath)
+		}
+
+		for key, value := range values {
+			answers[key] = value
+		}
+
+		entry := *applied
+		entry.Manifest = appliedManifest
+		history = append(history, &entry)
+		changes = append(changes, appliedChanges...)
+	}
+
+	if !hasManifest {
+		return errors.Newf("project %q has no build manifest; it was created by an older version of proji", project.Name)
+	}
+
+	if err = renderUpgradeChanges(changes); err != nil {
+		return errors.Wrap(err, "render upgrade changes")
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	logger.Debugf("updating project %q", project.ID)
+	err = prma.Update(ctx, &domain.ProjectUpdate{
+		ID:       project.ID,
+		Answers:  answers,
+		History:  history,
+		Manifest: manifest,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "update project %q", project.ID)
+	}
+
+	conflicts := 0
+	for _, change := range changes {
+		if change.Status == upgradeConflict || change.Status == upgradePatched {
+			conflicts++
+		}
+	}
+
+	if conflicts > 0 {
+		logger.Warnf("Upgraded project %q with %d file(s) that need manual attention", project.Name, conflicts)
+		return nil
+	}
+
+	logger.Infof("Successfully upgraded project %q", project.Name)
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package proji
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/projects/builder"
+)
+
+func TestUpgradeFile(t *testing.T) {
+	t.Parallel()
+
+	// withContent returns a manifest entry that keeps its content, like the ones of rendered templates.
+	withContent := func(content string) *domain.ManifestFile {
+		file := domain.NewManifestFile("a.txt", []byte(content))
+		file.Content = []byte(content)
+
+		return file
+	}
+	content := func(s string) *string { return &s }
+	hashOnly := func(content string) *domain.ManifestFile {
+		return domain.NewManifestFile("a.txt", []byte(content))
+	}
+
+	cases := []struct {
+		name        string
+		current     *string
+		base        *domain.ManifestFile
+		next        string
+		want        *upgradeChange
+		wantContent string
+	}{
+		{
+			name:        "created",
+			next:        "b\n",
+			want:        &upgradeChange{Status: upgradeCreated, Path: "a.txt"},
+			wantContent: "b\n",
+		},
+		{
+			name: "deleted by the user",
+			base: withContent("a\n"),
+			next: "b\n",
+			want: &upgradeChange{Status: upgradeKept, Path: "a.txt", Note: "deleted by the user"},
+		},
+		{
+			name:        "unchanged",
+			current:     content("b\n"),
+			base:        withContent("a\n"),
+			next:        "b\n",
+			want:        &upgradeChange{Status: upgradeUnchanged, Path: "a.txt"},
+			wantContent: "b\n",
+		},
+		{
+			name:        "updated against hash only base",
+			current:     content("a\n"),
+			base:        hashOnly("a\n"),
+			next:        "b\n",
+			want:        &upgradeChange{Status: upgradeUpdated, Path: "a.txt"},
+			wantContent: "b\n",
+		},
+		{
+			name:        "changed by the user",
+			current:     content("c\n"),
+			base:        hashOnly("a\n"),
+			next:        "a\n",
+			want:        &upgradeChange{Status: upgradeKept, Path: "a.txt", Note: "changed by the user"},
+			wantContent: "c\n",
+		},
+		{
+			name:    "no merge base",
+			current: content("c\n"),
+			base:    hashOnly("a\n"),
+			next:    "b\n",
+			want: &upgradeChange{
+				Status: upgradeKept, Path: "a.txt", Note: "changed by the user and the package; no merge base recorded",
+			},
+			wantContent: "c\n",
+		},
+		{
+			name:        "merged",
+			current:     content("user\na\nb\nc\n"),
+			base:        withContent("a\nb\nc\n"),
+			next:        "a\nb\nc\npackage\n",
+			want:        &upgradeChange{Status: upgradeMerged, Path: "a.txt"},
+			wantContent: "user\na\nb\nc\npackage\n",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			if tc.current != nil {
+				if err := afero.WriteFile(fs, "a.txt", []byte(*tc.current), 0o644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			next := withContent(tc.next)
+			got, err := upgradeFile(fs, tc.base, next, []byte(tc.next), "pkg", false, false)
+			if err != nil {
+				t.Fatalf("upgradeFile() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("upgradeFile() mismatch (-want +got):\n%s", diff)
+			}
+
+			if tc.current == nil && tc.base != nil {
+				return
+			}
+
+			data, err := afero.ReadFile(fs, "a.txt")
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			if string(data) != tc.wantContent {
+				t.Fatalf("expected content %q, got %q", tc.wantContent, data)
+			}
+		})
+	}
+}
+
+func TestUpgradeFiles_KeepsBaseOfUnappliedFiles(t *testing.T) {
+	t.Parallel()
+
+	// newRendered returns a builder holding a single rendered file with the given content.
+	newRendered := func(t *testing.T, content string) *builder.Builder {
+		t.Helper()
+
+		rendered := builder.New(afero.NewMemMapFs(), "", nil)
+		if err := afero.WriteFile(rendered.FS, "a.txt", []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		file := domain.NewManifestFile("a.txt", []byte(content))
+		file.Content = []byte(content)
+		rendered.Files = []*domain.ManifestFile{file}
+
+		return rendered
+	}
+
+	cases := []struct {
+		name       string
+		base       *domain.ManifestFile
+		patch      bool
+		wantStatus []upgradeStatus
+	}{
+		{
+			name:       "no merge base",
+			base:       domain.NewManifestFile("a.txt", []byte("a\nb\nc\n")),
+			wantStatus: []upgradeStatus{upgradeKept, upgradeKept},
+		},
+		{
+			name: "patched",
+			base: func() *domain.ManifestFile {
+				file := domain.NewManifestFile("a.txt", []byte("a\nb\nc\n"))
+				file.Content = []byte("a\nb\nc\n")
+				return file
+			}(),
+			patch:      true,
+			wantStatus: []upgradeStatus{upgradePatched, upgradePatched},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "a.txt", []byte("user\na\nb\nc\n"), 0o644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+
+			// Upgrade twice to the same new version; the second run must still see the package's changes
+			manifest := &domain.ProjectManifest{Files: []*domain.ManifestFile{tc.base}}
+			for idx, want := range tc.wantStatus {
+				changes, files, err := upgradeFiles(fs, manifest, newRendered(t, "a\nb\nc\npackage\n"), "pkg",
+					tc.patch, false)
+				if err != nil {
+					t.Fatalf("upgradeFiles() error = %v", err)
+				}
+
+				if len(changes) != 1 || changes[0].Status != want {
+					t.Fatalf("upgrade %d: expected status %q, got %+v", idx+1, want, changes)
+				}
+				if diff := cmp.Diff([]*domain.ManifestFile{tc.base}, files); diff != "" {
+					t.Fatalf("upgrade %d: base mismatch (-want +got):\n%s", idx+1, diff)
+				}
+
+				manifest = &domain.ProjectManifest{Files: files}
+			}
+
+			// Once the package's changes get merged, the new version becomes the base
+			changes, files, err := upgradeFiles(fs, manifest, newRendered(t, "a\nb\nc\npackage\n"), "pkg", false, false)
+			if err != nil {
+				t.Fatalf("upgradeFiles() error = %v", err)
+			}
+			if !tc.base.HasContent() {
+				return
+			}
+			if changes[0].Status != upgradeMerged {
+				t.Fatalf("expected status %q, got %q", upgradeMerged, changes[0].Status)
+			}
+			if !files[0].Matches([]byte("a\nb\nc\npackage\n")) {
+				t.Fatal("expected the merged version to become the new base")
+			}
+		})
+	}
+}
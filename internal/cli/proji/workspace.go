@@ -0,0 +1,446 @@
+package proji
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/proji/internal/cli"
+	"github.com/nikoksr/proji/internal/text"
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	projectRepo "github.com/nikoksr/proji/pkg/api/v1/project/repository/bolt"
+	workspaceRepo "github.com/nikoksr/proji/pkg/api/v1/workspace/repository/bolt"
+	database "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/fsutil"
+	"github.com/nikoksr/proji/pkg/workspaces"
+)
+
+// workspaceCommand returns a new instance of the workspace command. It groups commands that manage workspaces, named
+// groups of related projects.
+func workspaceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workspace",
+		Aliases: []string{"ws"},
+		Short:   "Manage workspaces of related projects",
+
+		Long: `Workspaces are named groups of related projects, e.g. the services of a microservice architecture. New
+projects can be created right inside of a workspace with 'proji new --workspace', and 'proji ls', 'proji status' and
+'proji project archive' accept a workspace to work on all of its projects at once.`,
+	}
+
+	cmd.AddCommand(
+		workspaceAddCommand(),
+		workspaceCreateCommand(),
+		workspaceDeleteCommand(),
+		workspaceListCommand(),
+		workspaceRemoveCommand(),
+	)
+
+	return cmd
+}
+
+func workspaceCreateCommand() *cobra.Command {
+	var root, description string
+
+	cmd := &cobra.Command{
+		Use:                   "create [OPTIONS] NAME",
+		Short:                 "Create a new workspace",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji workspace create shop
+  proji workspace create --root ~/code/shop --description "Online shop services" shop`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var desc *string
+			if cmd.Flags().Changed("description") {
+				desc = &description
+			}
+
+			return createWorkspace(cmd.Context(), args[0], root, desc)
+		},
+	}
+
+	cmd.Flags().StringVar(&root, "root", "", "Directory that new projects of the workspace get created in")
+	cmd.Flags().StringVar(&description, "description", "", "Description of the workspace")
+
+	return cmd
+}
+
+func workspaceAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "add NAME ID [ID...]",
+		Short:                 "Add projects to a workspace",
+		Args:                  cobra.MinimumNArgs(2),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji workspace add shop cf1l3q4bvs0e0m0ibmcg cf1l3vcbvs0e0m0ibmd0`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return addWorkspaceProjects(cmd.Context(), args[0], args[1:]...)
+		},
+	}
+
+	return cmd
+}
+
+func workspaceRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "rm NAME ID [ID...]",
+		Short:                 "Remove projects from a workspace",
+		Args:                  cobra.MinimumNArgs(2),
+		DisableFlagsInUseLine: true,
+
+		Long: `Removes projects from a workspace. The projects themselves stay tracked and untouched.`,
+
+		Example: `  proji workspace rm shop cf1l3q4bvs0e0m0ibmcg`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return removeWorkspaceProjects(cmd.Context(), args[0], args[1:]...)
+		},
+	}
+
+	return cmd
+}
+
+func workspaceDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "delete NAME [NAME...]",
+		Short:                 "Delete workspaces",
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Long: `Deletes workspaces. Their projects stay tracked and untouched.`,
+
+		Example: `  proji workspace delete shop`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteWorkspaces(cmd.Context(), args...)
+		},
+	}
+
+	return cmd
+}
+
+func workspaceListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "ls [NAME]",
+		Short:                 "List workspaces or the projects of a workspace",
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+
+		Example: `  proji workspace ls
+  proji workspace ls shop`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return listWorkspaces(cmd.Context())
+			}
+
+			return listWorkspaceProjects(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+// workspaceManager returns the workspace manager of the cli session.
+func workspaceManager(ctx context.Context) (workspaces.Manager, error) {
+	simplog.FromContext(ctx).Debug("getting workspace manager from cli session")
+	wsma := cli.SessionFromContext(ctx).WorkspaceManager
+	if wsma == nil {
+		return nil, errors.New("no workspace manager found")
+	}
+
+	return wsma, nil
+}
+
+// getWorkspace loads the workspace with the given name.
+func getWorkspace(ctx context.Context, name string) (*domain.Workspace, error) {
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := wsma.GetByName(ctx, name)
+	if errors.Is(err, database.ErrBucketNotFound) || errors.Is(err, workspaceRepo.ErrWorkspaceNotFound) {
+		err = errors.Newf("workspace %q does not exist", name)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "get workspace %q", name)
+	}
+
+	return &workspace, nil
+}
+
+// workspaceProjectPath resolves a relative project path against the root of the workspace. Absolute paths and
+// workspaces without a root leave the path untouched.
+func workspaceProjectPath(workspace *domain.Workspace, path string) string {
+	if workspace.Root == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(workspace.Root, path)
+}
+
+// workspaceProjects returns the projects of the workspace. Members that are no longer tracked are skipped.
+func workspaceProjects(ctx context.Context, workspace *domain.Workspace) ([]domain.Project, error) {
+	logger := simplog.FromContext(ctx)
+
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return nil, errors.New("no project manager found")
+	}
+
+	projectList := make([]domain.Project, 0, len(workspace.Projects))
+	for _, id := range workspace.Projects {
+		project, err := prma.GetByID(ctx, id)
+		if errors.Is(err, projectRepo.ErrProjectNotFound) {
+			logger.Debugf("skipping project %q of workspace %q; it's no longer tracked", id, workspace.Name)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "get project %q", id)
+		}
+
+		projectList = append(projectList, project)
+	}
+
+	return projectList, nil
+}
+
+// joinWorkspace adds the project to the workspace with the given name. An empty name is a no-op.
+func joinWorkspace(ctx context.Context, name, projectID string) error {
+	if name == "" {
+		return nil
+	}
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	simplog.FromContext(ctx).Debugf("adding project %q to workspace %q", projectID, name)
+	if err = wsma.Update(ctx, &domain.WorkspaceUpdate{Name: name, AddProjects: []string{projectID}}); err != nil {
+		return errors.Wrapf(err, "add project %q to workspace %q", projectID, name)
+	}
+
+	return nil
+}
+
+// forgetWorkspaceMember removes the project from all workspaces that it's a member of. It's meant to be called after
+// a project was removed; failures are only logged.
+func forgetWorkspaceMember(ctx context.Context, projectID string) {
+	logger := simplog.FromContext(ctx)
+
+	wsma := cli.SessionFromContext(ctx).WorkspaceManager
+	if wsma == nil {
+		return
+	}
+
+	workspaceList, err := wsma.Fetch(ctx)
+	if err != nil {
+		if !errors.Is(err, database.ErrBucketNotFound) {
+			logger.Warnf("Failed to remove project %q from its workspaces: %v", projectID, err)
+		}
+
+		return
+	}
+
+	for idx := range workspaceList {
+		if !workspaceList[idx].HasProject(projectID) {
+			continue
+		}
+
+		logger.Debugf("removing project %q from workspace %q", projectID, workspaceList[idx].Name)
+		err = wsma.Update(ctx, &domain.WorkspaceUpdate{Name: workspaceList[idx].Name, RemoveProjects: []string{projectID}})
+		if err != nil {
+			logger.Warnf("Failed to remove project %q from workspace %q: %v", projectID, workspaceList[idx].Name, err)
+		}
+	}
+}
+
+func createWorkspace(ctx context.Context, name, root string, description *string) error {
+	logger := simplog.FromContext(ctx)
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("workspace name must not be empty")
+	}
+
+	workspace := domain.NewWorkspace(name, "")
+	workspace.Description = description
+
+	if root = strings.TrimSpace(root); root != "" {
+		root, err = localPathToAbsPath(fsutil.ExpandPath(root))
+		if err != nil {
+			return errors.Wrapf(err, "get absolute path to %q", root)
+		}
+
+		workspace.Root = filepath.Clean(root)
+		if !doesPathExist(workspace.Root) {
+			logger.Warnf("Root directory %q of workspace %q does not exist yet", workspace.Root, name)
+		}
+	}
+
+	logger.Debugf("storing workspace %q", name)
+	if err = wsma.Store(ctx, workspace); err != nil {
+		return errors.Wrapf(err, "store workspace %q", name)
+	}
+
+	logger.Infof("Successfully created workspace %q", name)
+
+	return nil
+}
+
+func addWorkspaceProjects(ctx context.Context, name string, ids ...string) error {
+	logger := simplog.FromContext(ctx)
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	prma := cli.SessionFromContext(ctx).ProjectManager
+	if prma == nil {
+		return errors.New("no project manager found")
+	}
+
+	workspace, err := getWorkspace(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// Only tracked projects may become members
+	for _, id := range ids {
+		if _, err = prma.GetByID(ctx, id); err != nil {
+			return errors.Wrapf(err, "get project %q", id)
+		}
+	}
+
+	logger.Debugf("adding %d projects to workspace %q", len(ids), workspace.Name)
+	if err = wsma.Update(ctx, &domain.WorkspaceUpdate{Name: workspace.Name, AddProjects: ids}); err != nil {
+		return errors.Wrapf(err, "update workspace %q", workspace.Name)
+	}
+
+	logger.Infof("Successfully added %d projects to workspace %q", len(ids), workspace.Name)
+
+	return nil
+}
+
+func removeWorkspaceProjects(ctx context.Context, name string, ids ...string) error {
+	logger := simplog.FromContext(ctx)
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := getWorkspace(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if !workspace.HasProject(id) {
+			return errors.Newf("project %q is not a member of workspace %q", id, workspace.Name)
+		}
+	}
+
+	logger.Debugf("removing %d projects from workspace %q", len(ids), workspace.Name)
+	if err = wsma.Update(ctx, &domain.WorkspaceUpdate{Name: workspace.Name, RemoveProjects: ids}); err != nil {
+		return errors.Wrapf(err, "update workspace %q", workspace.Name)
+	}
+
+	logger.Infof("Successfully removed %d projects from workspace %q", len(ids), workspace.Name)
+
+	return nil
+}
+
+func deleteWorkspaces(ctx context.Context, names ...string) error {
+	logger := simplog.FromContext(ctx)
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, name := range names {
+		logger.Debugf("deleting workspace %q", name)
+		if err = wsma.Remove(ctx, name); err != nil {
+			logger.Warnf("Failed to delete workspace %q: %v", name, err)
+			failed++
+		} else {
+			logger.Infof("Successfully deleted workspace %q", name)
+		}
+	}
+
+	if failed > 0 {
+		return errors.Newf("failed to delete %d of %d workspaces", failed, len(names))
+	}
+
+	return nil
+}
+
+func listWorkspaces(ctx context.Context) error {
+	logger := simplog.FromContext(ctx)
+
+	wsma, err := workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("fetching workspace list")
+	workspaceList, err := wsma.Fetch(ctx)
+	if err != nil && !errors.Is(err, database.ErrBucketNotFound) {
+		return errors.Wrap(err, "fetch workspaces")
+	}
+	if len(workspaceList) == 0 {
+		logger.Info("No workspaces found; create one with 'proji workspace create'")
+		return nil
+	}
+
+	fmt.Println()
+	table := text.NewTablePrinter()
+	table.AddHeaderColumns("#", "Name", "Root", "Projects", "Description")
+
+	for idx, workspace := range workspaceList {
+		description := ""
+		if workspace.Description != nil {
+			description = *workspace.Description
+		}
+
+		table.AddRow(idx+1, workspace.Name, workspace.Root, len(workspace.Projects), description)
+	}
+
+	return table.Render()
+}
+
+func listWorkspaceProjects(ctx context.Context, name string) error {
+	workspace, err := getWorkspace(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	projectList, err := workspaceProjects(ctx, workspace)
+	if err != nil {
+		return errors.Wrapf(err, "load projects of workspace %q", workspace.Name)
+	}
+	if len(projectList) == 0 {
+		simplog.FromContext(ctx).Infof("Workspace %q has no projects yet", workspace.Name)
+		return nil
+	}
+
+	return renderProjects(projectList)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/nikoksr/proji/internal/config"
 	"github.com/nikoksr/proji/pkg/packages"
 	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/workspaces"
 )
 
 // Session is meant to provide commonly used functionality for CLI sessions. Another suitable name for this type would
@@ -21,10 +22,11 @@ import (
 // A simplog.Logger instance is not embedded since we use logging frequently outside a CLI's session, thus, to avoid
 // confusion, we don't embed it and instead handle loggers manually.
 type Session struct {
-	Debug          bool
-	Config         *config.Config
-	PackageManager packages.Manager
-	ProjectManager projects.Manager
+	Debug            bool
+	Config           *config.Config
+	PackageManager   packages.Manager
+	ProjectManager   projects.Manager
+	WorkspaceManager workspaces.Manager
 }
 
 // NewSessionWithMode creates a new session with the given debug mode.
@@ -61,6 +63,13 @@ func (session *Session) WithProjectManager(manager projects.Manager) *Session {
 	return session
 }
 
+// WithWorkspaceManager sets the given workspace manager on the session. It returns the session to allow chaining.
+func (session *Session) WithWorkspaceManager(manager workspaces.Manager) *Session {
+	session.WorkspaceManager = manager
+
+	return session
+}
+
 // As recommended by 'revive' linter.
 type contextKey string
 
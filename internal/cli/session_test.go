@@ -7,6 +7,7 @@ import (
 	"github.com/nikoksr/proji/internal/config"
 	"github.com/nikoksr/proji/pkg/packages"
 	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/workspaces"
 )
 
 func TestNewSession(t *testing.T) {
@@ -65,6 +66,13 @@ func TestSessionContext(t *testing.T) {
 		t.Errorf("Session.WithProjectManager() set %v, expected %v", session.ProjectManager, prma)
 	}
 
+	// Workspace manager
+	var wsma workspaces.Manager
+	session.WithWorkspaceManager(wsma)
+	if session.WorkspaceManager != wsma {
+		t.Errorf("Session.WithWorkspaceManager() set %v, expected %v", session.WorkspaceManager, wsma)
+	}
+
 	// Nil context
 	sessionFromContext = SessionFromContext(nil) //nolint:staticcheck
 	if sessionFromContext == nil {
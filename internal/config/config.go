@@ -35,6 +35,18 @@ type (
 		Exclude string `mapstructure:"exclude"`
 	}
 
+	// Projects is a configuration for the handling of tracked projects.
+	Projects struct {
+		// Root is the directory that new projects are suggested to be created in, e.g. by the interactive 'proji new'.
+		// Defaults to the current working directory.
+		Root string `mapstructure:"root"`
+		// ScanRoots are the directories that get searched for moved projects, e.g. by 'proji project relocate' and
+		// 'proji clean'.
+		ScanRoots []string `mapstructure:"scan_roots"`
+		// ScanDepth limits how many directory levels below a scan root are searched.
+		ScanDepth int `mapstructure:"scan_depth"`
+	}
+
 	// Sentry is a configuration for the Sentry monitoring service.
 	Sentry struct {
 		// Enabled is a flag that indicates if Sentry is enabled. This is disabled by default.
@@ -59,6 +71,7 @@ type (
 		Database   Database     `mapstructure:"database"`
 		Import     Import       `mapstructure:"import"`
 		Monitoring Monitoring   `mapstructure:"monitoring"`
+		Projects   Projects     `mapstructure:"projects"`
 		System     System       `mapstructure:"system"`
 		provider   *viper.Viper `mapstructure:"-"`
 	}
@@ -74,12 +87,14 @@ const (
 	defaultDatabaseFile = "proji.db"
 
 	// Other subdirectories
+	defaultArchivesDir  = "archives" // Inside the data directory
 	defaultPluginsDir   = "plugins"
 	defaultTemplatesDir = "templates"
 
 	// Some config constants/defaults
 	defaultExcludePattern = `^(.git|.env|.idea|.vscode)$`
 	defaultSentryState    = false
+	defaultScanDepth      = 4
 )
 
 var (
@@ -151,6 +166,7 @@ func newProvider(path string) *viper.Viper {
 	provider.SetDefault("database.dsn", filepath.Join(dir, defaultDataDir, defaultDatabaseFile))
 	provider.SetDefault("import.exclude", defaultExcludePattern)
 	provider.SetDefault("monitoring.sentry.enabled", defaultSentryState)
+	provider.SetDefault("projects.scan_depth", defaultScanDepth)
 
 	// Set configuration file path
 	provider.SetConfigFile(path)
@@ -331,6 +347,16 @@ func (conf *Config) BaseDir() string {
 	return filepath.Dir(conf.provider.ConfigFileUsed())
 }
 
+// DataDir returns the data directory.
+func (conf *Config) DataDir() string {
+	return filepath.Join(conf.BaseDir(), defaultDataDir)
+}
+
+// ArchivesDir returns the directory that archived projects are stored in.
+func (conf *Config) ArchivesDir() string {
+	return filepath.Join(conf.DataDir(), defaultArchivesDir)
+}
+
 // PluginsDir returns the plugins' directory.
 func (conf *Config) PluginsDir() string {
 	return filepath.Join(conf.BaseDir(), defaultPluginsDir)
@@ -45,6 +45,11 @@ func Test_load(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				Projects: Projects{
+					Root:      "/home/user_a/code",
+					ScanRoots: []string{"/home/user_a/code"},
+					ScanDepth: 2,
+				},
 				System: System{
 					TextEditor: "vim",
 				},
@@ -71,6 +76,9 @@ func Test_load(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				Projects: Projects{
+					ScanDepth: defaultScanDepth,
+				},
 				System: System{
 					TextEditor: "",
 				},
@@ -96,6 +104,9 @@ func Test_load(t *testing.T) {
 						Enabled: defaultSentryState,
 					},
 				},
+				Projects: Projects{
+					ScanDepth: defaultScanDepth,
+				},
 				System: System{
 					TextEditor: "",
 				},
@@ -13,9 +13,12 @@ import (
 	packageService "github.com/nikoksr/proji/pkg/api/v1/package/service"
 	projectRepo "github.com/nikoksr/proji/pkg/api/v1/project/repository/bolt"
 	projectService "github.com/nikoksr/proji/pkg/api/v1/project/service"
+	workspaceRepo "github.com/nikoksr/proji/pkg/api/v1/workspace/repository/bolt"
+	workspaceService "github.com/nikoksr/proji/pkg/api/v1/workspace/service"
 	database "github.com/nikoksr/proji/pkg/database/bolt"
 	"github.com/nikoksr/proji/pkg/packages"
 	"github.com/nikoksr/proji/pkg/projects"
+	"github.com/nikoksr/proji/pkg/workspaces"
 )
 
 // TODO: Should probably be configurable.
@@ -100,3 +103,24 @@ func NewProjectManager(ctx context.Context, db *database.DB) (projects.Manager,
 	// Create the local project manager.
 	return projects.NewManager(service)
 }
+
+// NewWorkspaceManager returns a new workspace manager. Like the project manager, it is always local.
+func NewWorkspaceManager(ctx context.Context, db *database.DB) (workspaces.Manager, error) {
+	logger := simplog.FromContext(ctx)
+
+	// Create the workspace manager.
+	logger.Debugf("creating a workspace manager")
+
+	repo, err := workspaceRepo.New(db)
+	if err != nil {
+		return nil, errors.Wrap(err, "create workspace repository")
+	}
+
+	service, err := workspaceService.New(defaultServiceTimeout, repo)
+	if err != nil {
+		return nil, errors.Wrap(err, "create workspace service")
+	}
+
+	// Create the local workspace manager.
+	return workspaces.NewManager(service)
+}
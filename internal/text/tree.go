@@ -0,0 +1,93 @@
+package text
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// treeNode is a single file or directory of a rendered tree.
+type treeNode struct {
+	name     string
+	isDir    bool
+	children map[string]*treeNode
+}
+
+func newTreeNode(name string, isDir bool) *treeNode {
+	return &treeNode{name: name, isDir: isDir, children: make(map[string]*treeNode)}
+}
+
+// insert adds the path below the node. Missing parent directories are added implicitly.
+func (n *treeNode) insert(path string) {
+	isDir := strings.HasSuffix(path, "/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	node := n
+	for idx, part := range parts {
+		if part == "" || part == "." {
+			continue
+		}
+
+		child, exists := node.children[part]
+		if !exists {
+			child = newTreeNode(part, isDir || idx < len(parts)-1)
+			node.children[part] = child
+		}
+
+		node = child
+	}
+}
+
+// sortedChildren returns the children of the node; directories come first, each group is sorted by name.
+func (n *treeNode) sortedChildren() []*treeNode {
+	children := make([]*treeNode, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].isDir != children[j].isDir {
+			return children[i].isDir
+		}
+
+		return children[i].name < children[j].name
+	})
+
+	return children
+}
+
+// render writes the children of the node to the builder. The prefix holds the branch lines of all parents.
+func (n *treeNode) render(b *strings.Builder, prefix string) {
+	children := n.sortedChildren()
+	for idx, child := range children {
+		connector, indent := "├── ", "│   "
+		if idx == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		b.WriteString(prefix + connector + child.name)
+		if child.isDir {
+			b.WriteString("/")
+		}
+		b.WriteString("\n")
+
+		child.render(b, prefix+indent)
+	}
+}
+
+// RenderTree writes the given paths as a tree below root to the writer. Paths are expected to be relative to root and
+// separated by slashes; paths that end with a slash are rendered as directories. Directories are listed before files.
+func RenderTree(w io.Writer, root string, paths []string) error {
+	tree := newTreeNode(root, true)
+	for _, path := range paths {
+		tree.insert(path)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(root, "/") + "/\n")
+	tree.render(&b, "")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
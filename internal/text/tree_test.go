@@ -0,0 +1,65 @@
+package text
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderTree(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		root  string
+		paths []string
+		want  string
+	}{
+		{
+			name: "empty",
+			root: "app",
+			want: "app/\n",
+		},
+		{
+			name:  "nested",
+			root:  "app/",
+			paths: []string{"x.txt", "docs/README.md", "src/", "docs/", "src/main/app.go", "a.txt"},
+			want: `app/
+├── docs/
+│   └── README.md
+├── src/
+│   └── main/
+│       └── app.go
+├── a.txt
+└── x.txt
+`,
+		},
+		{
+			name:  "implicit parents",
+			root:  "app",
+			paths: []string{"./cmd/app/main.go", "cmd/app/"},
+			want: `app/
+└── cmd/
+    └── app/
+        └── main.go
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := RenderTree(&buf, tc.root, tc.paths); err != nil {
+				t.Fatalf("RenderTree() returned an unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Fatalf("RenderTree() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
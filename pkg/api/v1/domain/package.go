@@ -46,8 +46,11 @@ type (
 		UpstreamURL *string          `json:"upstream_url,omitempty" toml:"upstream_url,omitempty"`
 		SHA         *string          `json:"sha,omitempty" toml:"sha,omitempty"`
 		Description *string          `json:"description,omitempty" toml:"description,omitempty"`
+		Destination *string          `json:"destination,omitempty" toml:"destination,omitempty"`
+		Notes       *string          `json:"notes,omitempty" toml:"notes,omitempty"` // Printed after a project was created
 		DirTree     *DirTree         `json:"dir_tree,omitempty" toml:"dir_tree,omitempty"`
 		Plugins     *PluginScheduler `json:"plugins,omitempty" toml:"plugins,omitempty"`
+		Revision    int              `json:"revision,omitempty" toml:"revision,omitempty"` // Incremented on every update
 		CreatedAt   time.Time        `json:"created_at" toml:"created_at"`
 		UpdatedAt   time.Time        `json:"updated_at" toml:"updated_at"`
 	}
@@ -63,6 +66,8 @@ type (
 		UpstreamURL *string                `json:"upstream_url,omitempty" toml:"upstream_url,omitempty"`
 		SHA         *string                `json:"sha,omitempty" toml:"sha,omitempty"`
 		Description *string                `json:"description,omitempty" toml:"description,omitempty"`
+		Destination *string                `json:"destination,omitempty" toml:"destination,omitempty"`
+		Notes       *string                `json:"notes,omitempty" toml:"notes,omitempty"`
 		DirTree     *DirTreeConfig         `json:"dir_tree,omitempty" toml:"dir_tree,omitempty"`
 		Plugins     *PluginSchedulerConfig `json:"plugins,omitempty" toml:"plugins,omitempty"`
 	}
@@ -74,8 +79,11 @@ type (
 		UpstreamURL *string          `json:"upstream_url,omitempty" toml:"upstream_url,omitempty"`
 		SHA         *string          `json:"sha,omitempty" toml:"sha,omitempty"`
 		Description *string          `json:"description,omitempty" toml:"description,omitempty"`
+		Destination *string          `json:"destination,omitempty" toml:"destination,omitempty"`
+		Notes       *string          `json:"notes,omitempty" toml:"notes,omitempty"`
 		DirTree     *DirTree         `json:"dir_tree,omitempty" toml:"dir_tree,omitempty"`
 		Plugins     *PluginScheduler `json:"plugins,omitempty" toml:"plugins,omitempty"`
+		Source      string           `json:"source,omitempty" toml:"-"` // Recorded in the revision history
 	}
 
 	// PackageUpdate is used to update packages in the database.
@@ -85,8 +93,20 @@ type (
 		UpstreamURL *string          `json:"upstream_url,omitempty" toml:"upstream_url,omitempty"`
 		SHA         *string          `json:"sha,omitempty" toml:"sha,omitempty"`
 		Description *string          `json:"description,omitempty" toml:"description,omitempty"`
+		Destination *string          `json:"destination,omitempty" toml:"destination,omitempty"`
+		Notes       *string          `json:"notes,omitempty" toml:"notes,omitempty"`
 		DirTree     *DirTree         `json:"dir_tree,omitempty" toml:"dir_tree,omitempty"`
 		Plugins     *PluginScheduler `json:"plugins,omitempty" toml:"plugins,omitempty"`
+		Source      string           `json:"source,omitempty" toml:"-"` // Recorded in the revision history
+	}
+
+	// PackageRevision is a past or present definition of a package. Every change of a package is kept as a numbered
+	// revision, so that it can be compared with other revisions and restored later on.
+	PackageRevision struct {
+		Revision  int       `json:"revision" toml:"revision"`
+		Source    string    `json:"source,omitempty" toml:"source,omitempty"` // Where the definition came from
+		CreatedAt time.Time `json:"created_at" toml:"created_at"`
+		Package   *Package  `json:"package" toml:"package"`
 	}
 
 	// PackageService is used to manage packages, typically by calling a PackageRepo under the hood.
@@ -97,6 +117,8 @@ type (
 		Update(ctx context.Context, _package *PackageUpdate) error
 		UpdateFromUpstream(ctx context.Context, _package *PackageUpdate) error
 		Remove(ctx context.Context, label string) error
+		History(ctx context.Context, label string) ([]PackageRevision, error)
+		GetRevision(ctx context.Context, label string, revision int) (PackageRevision, error)
 	}
 
 	// PackageRepo is used to fetch packages from the database.
@@ -106,16 +128,27 @@ type (
 		Store(ctx context.Context, _package *PackageAdd) error
 		Update(ctx context.Context, _package *PackageUpdate) error
 		Remove(ctx context.Context, label string) error
+		History(ctx context.Context, label string) ([]PackageRevision, error)
+		GetRevision(ctx context.Context, label string, revision int) (PackageRevision, error)
 	}
 )
 
-const bucketPackages = "packages"
+const (
+	bucketPackages         = "packages"
+	bucketPackageRevisions = "package_revisions"
+)
 
 // Bucket returns the bucket name for the package.
 func (*Package) Bucket() string {
 	return bucketPackages
 }
 
+// Bucket returns the bucket name for package revisions. Revisions of a package are kept in a nested bucket that is
+// named after the package's label.
+func (*PackageRevision) Bucket() string {
+	return bucketPackageRevisions
+}
+
 // MarshalJSON marshals the package into JSON. It is used to dynamically add timestamps for the created_at and
 // updated_at fields.
 func (p *PackageAdd) MarshalJSON() ([]byte, error) {
@@ -159,6 +192,8 @@ func (p *Package) AsUpdatable() *PackageUpdate {
 		UpstreamURL: p.UpstreamURL,
 		SHA:         p.SHA,
 		Description: p.Description,
+		Destination: p.Destination,
+		Notes:       p.Notes,
 		DirTree:     p.DirTree,
 		Plugins:     p.Plugins,
 	}
@@ -216,6 +251,8 @@ func (p *Package) ToConfig() *PackageConfig {
 		UpstreamURL: p.UpstreamURL,
 		SHA:         p.SHA,
 		Description: p.Description,
+		Destination: p.Destination,
+		Notes:       p.Notes,
 		DirTree:     p.DirTree.ToConfig(),
 		Plugins:     p.Plugins.ToConfig(),
 	}
@@ -2,7 +2,12 @@ package domain
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/rs/xid"
@@ -12,30 +17,97 @@ type (
 	// Project represents a package. Project is meant to be used for display purposes as it loads all info about a
 	// package that might of interest to the user. It is not meant to be used for storage purposes.
 	Project struct {
-		ID          string    `json:"id" toml:"id"`
-		Path        string    `json:"path" toml:"path"`
-		Name        string    `json:"name" toml:"name"`
-		Package     string    `json:"package" toml:"package"`
-		Description *string   `json:"description,omitempty" toml:"description,omitempty"`
-		CreatedAt   time.Time `json:"created_at" toml:"created_at"`
-		UpdatedAt   time.Time `json:"updated_at" toml:"updated_at"`
+		ID          string                 `json:"id" toml:"id"`
+		Path        string                 `json:"path" toml:"path"`
+		Name        string                 `json:"name" toml:"name"`
+		Package     string                 `json:"package" toml:"package"`
+		Description *string                `json:"description,omitempty" toml:"description,omitempty"`
+		Tags        []string               `json:"tags,omitempty" toml:"tags,omitempty"`
+		Answers     map[string]string      `json:"answers,omitempty" toml:"answers,omitempty"`
+		History     []*ProjectHistoryEntry `json:"history,omitempty" toml:"history,omitempty"`
+		Manifest    *ProjectManifest       `json:"manifest,omitempty" toml:"manifest,omitempty"`
+		Archive     *ProjectArchive        `json:"archive,omitempty" toml:"archive,omitempty"` // Set if archived
+		CreatedAt   time.Time              `json:"created_at" toml:"created_at"`
+		UpdatedAt   time.Time              `json:"updated_at" toml:"updated_at"`
+	}
+
+	// ProjectHistoryEntry records a package that was applied to an already existing project, for example by
+	// 'proji add'.
+	ProjectHistoryEntry struct {
+		Package   string           `json:"package" toml:"package"`                       // Label of the applied package
+		Subpath   string           `json:"subpath,omitempty" toml:"subpath,omitempty"`   // Path relative to the project root
+		Manifest  *ProjectManifest `json:"manifest,omitempty" toml:"manifest,omitempty"` // Paths are relative to the subpath
+		AppliedAt time.Time        `json:"applied_at" toml:"applied_at"`
+	}
+
+	// ProjectArchive describes the compressed archive that an archived project's directory was packed into. The
+	// project's directory doesn't exist while it's archived.
+	ProjectArchive struct {
+		Path       string    `json:"path" toml:"path"`
+		Size       int64     `json:"size" toml:"size"` // Size of the archive in bytes
+		ArchivedAt time.Time `json:"archived_at" toml:"archived_at"`
+	}
+
+	// ProjectManifest records what proji produced when it built a project: the packages it was built from, in layer
+	// order, the resolved template values, the generated directories and files, and the plugins that were run. It
+	// allows to compare, regenerate and audit projects later on.
+	ProjectManifest struct {
+		Packages []*ManifestPackage `json:"packages" toml:"packages"`
+		Answers  map[string]string  `json:"answers,omitempty" toml:"answers,omitempty"`
+		Files    []*ManifestFile    `json:"files,omitempty" toml:"files,omitempty"`
+		Plugins  []*ManifestPlugin  `json:"plugins,omitempty" toml:"plugins,omitempty"`
+		BuiltAt  time.Time          `json:"built_at" toml:"built_at"`
+	}
+
+	// ManifestPackage identifies the exact version of a package that a project was built from.
+	ManifestPackage struct {
+		Label    string  `json:"label" toml:"label"`
+		Revision int     `json:"revision" toml:"revision"`
+		SHA      *string `json:"sha,omitempty" toml:"sha,omitempty"` // Upstream SHA of the package, if any
+	}
+
+	// ManifestFile is a directory or file that was generated by a build. Paths are slash-separated and relative to the
+	// project root. Files are identified by the hash of the content they were generated with; the content itself is
+	// only kept for files that were rendered from templates, as upgrades need it as base for merges.
+	ManifestFile struct {
+		Path    string `json:"path" toml:"path"`
+		IsDir   bool   `json:"is_dir,omitempty" toml:"is_dir,omitempty"`
+		SHA256  string `json:"sha256,omitempty" toml:"sha256,omitempty"`
+		Content []byte `json:"content,omitempty" toml:"content,omitempty"`
+	}
+
+	// ManifestPlugin is a plugin that was run by a build.
+	ManifestPlugin struct {
+		Stage string `json:"stage" toml:"stage"` // Either 'pre' or 'post'
+		Path  string `json:"path" toml:"path"`
 	}
 
 	// ProjectAdd is used to add new packages to the database.
 	ProjectAdd struct {
-		Path        string  `json:"path" toml:"path"`
-		Name        string  `json:"name" toml:"name"`
-		Package     string  `json:"package" toml:"package"`
-		Description *string `json:"description,omitempty" toml:"description,omitempty"`
+		ID          string                 `json:"id" toml:"id"`
+		Path        string                 `json:"path" toml:"path"`
+		Name        string                 `json:"name" toml:"name"`
+		Package     string                 `json:"package" toml:"package"`
+		Description *string                `json:"description,omitempty" toml:"description,omitempty"`
+		Tags        []string               `json:"tags,omitempty" toml:"tags,omitempty"`
+		Answers     map[string]string      `json:"answers,omitempty" toml:"answers,omitempty"`
+		History     []*ProjectHistoryEntry `json:"history,omitempty" toml:"history,omitempty"`
+		Manifest    *ProjectManifest       `json:"manifest,omitempty" toml:"manifest,omitempty"`
 	}
 
-	// ProjectUpdate is used to update packages in the database.
+	// ProjectUpdate is used to update packages in the database. Empty fields are left untouched.
 	ProjectUpdate struct {
-		ID          string  `json:"id" toml:"id"`
-		Path        string  `json:"path" toml:"path"`
-		Name        string  `json:"name,omitempty" toml:"name,omitempty"`
-		Package     string  `json:"package,omitempty" toml:"package,omitempty"`
-		Description *string `json:"description,omitempty" toml:"description,omitempty"`
+		ID          string                 `json:"id" toml:"id"`
+		Path        string                 `json:"path,omitempty" toml:"path,omitempty"`
+		Name        string                 `json:"name,omitempty" toml:"name,omitempty"`
+		Package     string                 `json:"package,omitempty" toml:"package,omitempty"`
+		Description *string                `json:"description,omitempty" toml:"description,omitempty"` // Empty clears it
+		Tags        []string               `json:"tags,omitempty" toml:"tags,omitempty"`
+		Answers     map[string]string      `json:"answers,omitempty" toml:"answers,omitempty"`
+		History     []*ProjectHistoryEntry `json:"history,omitempty" toml:"history,omitempty"`
+		Manifest    *ProjectManifest       `json:"manifest,omitempty" toml:"manifest,omitempty"`
+		Archive     *ProjectArchive        `json:"archive,omitempty" toml:"archive,omitempty"`
+		Unarchive   bool                   `json:"unarchive,omitempty" toml:"unarchive,omitempty"` // Clears Archive
 	}
 
 	// ProjectService is used to manage packages, typically by calling a ProjectRepo under the hood.
@@ -67,23 +139,138 @@ func (p *ProjectAdd) MarshalJSON() ([]byte, error) {
 
 	// ID should be applied only if it is not set. We overwrite the ID field with a new ID if it is not set so that
 	// the marshaled JSON contains the new ID as well as the receiving project instance.
-	/*	if p.ID == "" {
-			p.ID = xid.New().String()
-		}
-	*/
+	if p.ID == "" {
+		p.ID = xid.New().String()
+	}
+
 	return json.Marshal(&struct {
 		*Alias
-		ID        string    `json:"id"`
 		CreatedAt time.Time `json:"created_at"`
 		UpdatedAt time.Time `json:"updated_at"`
 	}{
 		Alias:     (*Alias)(p),
-		ID:        xid.New().String(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	})
 }
 
+// ApplyUpdate applies the non-empty fields of the given update to the project and bumps its UpdatedAt timestamp.
+// The project's ID and creation date are never changed.
+func (p *Project) ApplyUpdate(update *ProjectUpdate) {
+	if update == nil {
+		return
+	}
+
+	if update.Path != "" {
+		p.Path = update.Path
+	}
+	if update.Name != "" {
+		p.Name = update.Name
+	}
+	if update.Package != "" {
+		p.Package = update.Package
+	}
+	if update.Description != nil {
+		p.Description = update.Description
+		if strings.TrimSpace(*update.Description) == "" {
+			p.Description = nil // An empty description clears it
+		}
+	}
+	if update.Tags != nil {
+		p.Tags = update.Tags
+	}
+	if update.Answers != nil {
+		p.Answers = update.Answers
+	}
+	if update.History != nil {
+		p.History = update.History
+	}
+	if update.Manifest != nil {
+		p.Manifest = update.Manifest
+	}
+	if update.Archive != nil {
+		p.Archive = update.Archive
+	}
+	if update.Unarchive {
+		p.Archive = nil
+	}
+
+	p.UpdatedAt = time.Now()
+}
+
+// HasTag checks whether the project is tagged with the given tag. Tags are compared case-insensitively.
+func (p *Project) HasTag(tag string) bool {
+	tag = normalizeTag(tag)
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeTag trims and lowercases the tag.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags trims, lowercases, deduplicates and sorts the given tags. Empty tags are dropped. It never returns nil,
+// so that the result can be used to clear the tags of a project.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, exists := seen[tag]; exists {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+// NewManifestFile returns a manifest entry for a file with the given content. Only the content's SHA-256 hash gets
+// recorded; callers that need the content later on have to set it themselves.
+func NewManifestFile(path string, content []byte) *ManifestFile {
+	return &ManifestFile{
+		Path:   filepath.ToSlash(path),
+		SHA256: hashContent(content),
+	}
+}
+
+// Matches reports whether the given content is the content that the file was generated with.
+func (f *ManifestFile) Matches(content []byte) bool {
+	return f.SHA256 == hashContent(content)
+}
+
+// HasContent reports whether the content that the file was generated with is known. Files that were generated empty
+// need no recorded content.
+func (f *ManifestFile) HasContent() bool {
+	return len(f.Content) > 0 || f.Matches(nil)
+}
+
+// hashContent returns the hex encoded SHA-256 hash of the given content.
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// NewManifestDir returns a manifest entry for a directory.
+func NewManifestDir(path string) *ManifestFile {
+	return &ManifestFile{Path: filepath.ToSlash(path), IsDir: true}
+}
+
 // NewProject creates a new package with the given name and label.
 func NewProject(packageLabel, path, name string) *ProjectAdd {
 	return &ProjectAdd{
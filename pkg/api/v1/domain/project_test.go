@@ -135,3 +135,209 @@ func TestProjectAdd_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestProject_ApplyUpdate(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Now().Add(-time.Hour)
+	history := []*ProjectHistoryEntry{{Package: "dkr", Subpath: "deploy"}}
+	archive := &ProjectArchive{Path: "/data/archives/abc.tar.gz", Size: 42, ArchivedAt: createdAt}
+
+	cases := []struct {
+		name    string
+		current *Project // Defaults to a project without optional fields
+		update  *ProjectUpdate
+		want    *Project
+	}{
+		{
+			name:   "nil update",
+			update: nil,
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "empty update keeps all fields",
+			update: &ProjectUpdate{ID: "abc"},
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "update all fields",
+			update: &ProjectUpdate{
+				ID:          "xyz",
+				Path:        "/some/where/else",
+				Name:        "renamed",
+				Package:     "pkg",
+				Description: stringToPointer("Some description."),
+				Tags:        []string{"client-a"},
+				Answers:     map[string]string{"projectname": "renamed"},
+				History:     history,
+			},
+			want: &Project{
+				ID:          "abc",
+				Path:        "/some/where/else",
+				Name:        "renamed",
+				Package:     "pkg",
+				Description: stringToPointer("Some description."),
+				Tags:        []string{"client-a"},
+				Answers:     map[string]string{"projectname": "renamed"},
+				History:     history,
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name: "empty description clears it",
+			current: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", Description: stringToPointer("Old."),
+				CreatedAt: createdAt,
+			},
+			update: &ProjectUpdate{ID: "abc", Description: stringToPointer(" ")},
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "nil description keeps it",
+			current: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", Description: stringToPointer("Old."),
+				CreatedAt: createdAt,
+			},
+			update: &ProjectUpdate{ID: "abc", Name: "renamed"},
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "renamed", Package: "tst", Description: stringToPointer("Old."),
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "archive",
+			update: &ProjectUpdate{ID: "abc", Archive: archive},
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", Archive: archive, CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "unarchive",
+			update: &ProjectUpdate{ID: "abc", Archive: archive, Unarchive: true},
+			want: &Project{
+				ID: "abc", Path: "/some/where", Name: "test", Package: "tst", CreatedAt: createdAt,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := &Project{ID: "abc", Path: "/some/where", Name: "test", Package: "tst", CreatedAt: createdAt}
+			if tc.current != nil {
+				got = tc.current
+			}
+			got.ApplyUpdate(tc.update)
+
+			diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Project{}, "UpdatedAt"))
+			if diff != "" {
+				t.Fatalf("ApplyUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "empty tags are dropped", tags: []string{"", "  "}, want: []string{}},
+		{
+			name: "normalized",
+			tags: []string{" Team-B", "client-a", "team-b", "CLIENT-A"},
+			want: []string{"client-a", "team-b"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NormalizeTags(tc.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("NormalizeTags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProject_HasTag(t *testing.T) {
+	t.Parallel()
+
+	project := &Project{Tags: []string{"client-a", "wip"}}
+	if !project.HasTag(" WIP") {
+		t.Fatal("HasTag() didn't find tag 'wip'")
+	}
+	if project.HasTag("client-b") {
+		t.Fatal("HasTag() found tag 'client-b'")
+	}
+}
+
+func TestNewManifestFile(t *testing.T) {
+	t.Parallel()
+
+	got := NewManifestFile("docs/README.md", []byte("hello"))
+	want := &ManifestFile{
+		Path:   "docs/README.md",
+		SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("NewManifestFile() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestManifestFile_Matches(t *testing.T) {
+	t.Parallel()
+
+	file := NewManifestFile("docs/README.md", []byte("hello"))
+	if !file.Matches([]byte("hello")) {
+		t.Fatal("Matches() didn't match the generated content")
+	}
+	if file.Matches([]byte("hello, world")) {
+		t.Fatal("Matches() matched changed content")
+	}
+}
+
+func TestManifestFile_HasContent(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		file *ManifestFile
+		want bool
+	}{
+		{name: "hash only", file: NewManifestFile("a.txt", []byte("hello")), want: false},
+		{name: "empty", file: NewManifestFile("a.txt", nil), want: true},
+		{
+			name: "content",
+			file: &ManifestFile{Path: "a.txt", SHA256: hashContent([]byte("hello")), Content: []byte("hello")},
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.file.HasContent(); got != tc.want {
+				t.Fatalf("HasContent() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
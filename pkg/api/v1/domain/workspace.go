@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type (
+	// Workspace is a named group of related projects, e.g. the services of a microservice architecture. A workspace
+	// may have a root directory that new projects of the workspace get created in. Workspaces are identified by their
+	// name.
+	Workspace struct {
+		Name        string    `json:"name" toml:"name"`
+		Root        string    `json:"root,omitempty" toml:"root,omitempty"`
+		Description *string   `json:"description,omitempty" toml:"description,omitempty"`
+		Projects    []string  `json:"projects,omitempty" toml:"projects,omitempty"` // IDs of the member projects
+		CreatedAt   time.Time `json:"created_at" toml:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at" toml:"updated_at"`
+	}
+
+	// WorkspaceAdd is used to add new workspaces to the database.
+	WorkspaceAdd struct {
+		Name        string   `json:"name" toml:"name"`
+		Root        string   `json:"root,omitempty" toml:"root,omitempty"`
+		Description *string  `json:"description,omitempty" toml:"description,omitempty"`
+		Projects    []string `json:"projects,omitempty" toml:"projects,omitempty"`
+	}
+
+	// WorkspaceUpdate is used to update workspaces in the database. Empty fields are left untouched.
+	WorkspaceUpdate struct {
+		Name           string   `json:"name" toml:"name"`
+		Root           string   `json:"root,omitempty" toml:"root,omitempty"`
+		Description    *string  `json:"description,omitempty" toml:"description,omitempty"`
+		AddProjects    []string `json:"add_projects,omitempty" toml:"add_projects,omitempty"`
+		RemoveProjects []string `json:"remove_projects,omitempty" toml:"remove_projects,omitempty"`
+	}
+
+	// WorkspaceService is used to manage workspaces, typically by calling a WorkspaceRepo under the hood.
+	WorkspaceService interface {
+		Fetch(ctx context.Context) ([]Workspace, error)
+		GetByName(ctx context.Context, name string) (Workspace, error)
+		Store(ctx context.Context, workspace *WorkspaceAdd) error
+		Update(ctx context.Context, workspace *WorkspaceUpdate) error
+		Remove(ctx context.Context, name string) error
+	}
+
+	// WorkspaceRepo is used to fetch workspaces from the database.
+	WorkspaceRepo interface {
+		WorkspaceService
+	}
+)
+
+const bucketWorkspaces = "workspaces"
+
+// Bucket returns the bucket name for the workspace.
+func (Workspace) Bucket() string {
+	return bucketWorkspaces
+}
+
+// MarshalJSON marshals the workspace into JSON. It is used to dynamically add timestamps for the created_at and
+// updated_at fields.
+func (w *WorkspaceAdd) MarshalJSON() ([]byte, error) {
+	type Alias WorkspaceAdd
+
+	return json.Marshal(&struct {
+		*Alias
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}{
+		Alias:     (*Alias)(w),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+}
+
+// ApplyUpdate applies the non-empty fields of the given update to the workspace and bumps its UpdatedAt timestamp.
+// Projects that are already members aren't added twice; the order of the members is kept.
+func (w *Workspace) ApplyUpdate(update *WorkspaceUpdate) {
+	if update == nil {
+		return
+	}
+
+	if update.Root != "" {
+		w.Root = update.Root
+	}
+	if update.Description != nil {
+		w.Description = update.Description
+	}
+
+	for _, id := range update.AddProjects {
+		if !w.HasProject(id) {
+			w.Projects = append(w.Projects, id)
+		}
+	}
+
+	if len(update.RemoveProjects) > 0 {
+		remove := make(map[string]struct{}, len(update.RemoveProjects))
+		for _, id := range update.RemoveProjects {
+			remove[id] = struct{}{}
+		}
+
+		kept := make([]string, 0, len(w.Projects))
+		for _, id := range w.Projects {
+			if _, exists := remove[id]; !exists {
+				kept = append(kept, id)
+			}
+		}
+		w.Projects = kept
+	}
+
+	w.UpdatedAt = time.Now()
+}
+
+// HasProject checks whether the project with the given ID is a member of the workspace.
+func (w *Workspace) HasProject(id string) bool {
+	for _, member := range w.Projects {
+		if member == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewWorkspace creates a new workspace with the given name and root directory.
+func NewWorkspace(name, root string) *WorkspaceAdd {
+	return &WorkspaceAdd{
+		Name: name,
+		Root: root,
+	}
+}
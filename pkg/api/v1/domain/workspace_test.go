@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWorkspace_Bucket(t *testing.T) {
+	t.Parallel()
+
+	workspace := Workspace{}
+	bucket := workspace.Bucket()
+	if bucket != bucketWorkspaces {
+		t.Fatalf("expected bucket to be %q, got %s", bucketWorkspaces, bucket)
+	}
+}
+
+func TestWorkspace_ApplyUpdate(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name   string
+		update *WorkspaceUpdate
+		want   *Workspace
+	}{
+		{
+			name:   "nil update",
+			update: nil,
+			want:   &Workspace{Name: "shop", Root: "/code/shop", Projects: []string{"a", "b"}, CreatedAt: createdAt},
+		},
+		{
+			name:   "update root and description",
+			update: &WorkspaceUpdate{Name: "other", Root: "/srv/shop", Description: stringToPointer("Online shop.")},
+			want: &Workspace{
+				Name:        "shop",
+				Root:        "/srv/shop",
+				Description: stringToPointer("Online shop."),
+				Projects:    []string{"a", "b"},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name:   "add projects without duplicates",
+			update: &WorkspaceUpdate{Name: "shop", AddProjects: []string{"c", "a", "c"}},
+			want: &Workspace{
+				Name: "shop", Root: "/code/shop", Projects: []string{"a", "b", "c"}, CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "remove projects",
+			update: &WorkspaceUpdate{Name: "shop", RemoveProjects: []string{"a", "x"}},
+			want:   &Workspace{Name: "shop", Root: "/code/shop", Projects: []string{"b"}, CreatedAt: createdAt},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := &Workspace{Name: "shop", Root: "/code/shop", Projects: []string{"a", "b"}, CreatedAt: createdAt}
+			got.ApplyUpdate(tc.update)
+
+			diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Workspace{}, "UpdatedAt"))
+			if diff != "" {
+				t.Fatalf("ApplyUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspace_HasProject(t *testing.T) {
+	t.Parallel()
+
+	workspace := &Workspace{Projects: []string{"a", "b"}}
+	if !workspace.HasProject("b") {
+		t.Fatal("HasProject() didn't find project 'b'")
+	}
+	if workspace.HasProject("c") {
+		t.Fatal("HasProject() found project 'c'")
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi"
 	"go.uber.org/zap"
@@ -28,6 +29,8 @@ func Register(logger *zap.Logger, router *chi.Mux, manager packages.Manager) {
 	router.Post("/api/v1/packages", handler.Create)
 	router.Put("/api/v1/packages/{label}", handler.Update)
 	router.Delete("/api/v1/packages/{label}", handler.Delete)
+	router.Get("/api/v1/packages/{label}/revisions", handler.History)
+	router.Get("/api/v1/packages/{label}/revisions/{revision}", handler.GetRevision)
 }
 
 func (h *packageHandler) Fetch(w http.ResponseWriter, r *http.Request) {
@@ -132,3 +135,54 @@ func (h *packageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *packageHandler) History(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	label := chi.URLParam(r, "label")
+
+	history, err := h.manager.History(r.Context(), label)
+	if err != nil {
+		h.logger.Error("failed to get package history", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	dataJSON, err := json.Marshal(history)
+	if err != nil {
+		h.logger.Error("failed to marshal package history", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(dataJSON)
+}
+
+func (h *packageHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	label := chi.URLParam(r, "label")
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		h.logger.Error("failed to parse revision", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.manager.GetRevision(r.Context(), label, revision)
+	if err != nil {
+		h.logger.Error("failed to get package revision", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	dataJSON, err := json.Marshal(stored)
+	if err != nil {
+		h.logger.Error("failed to marshal package revision", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(dataJSON)
+}
@@ -2,7 +2,10 @@ package bolt
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
+	"sort"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	bolt "go.etcd.io/bbolt"
@@ -16,11 +19,14 @@ var (
 	ErrPackageNotFound = errors.New("package not found")
 	// ErrPackageExists is returned when a package with the same label already exists in the repository.
 	ErrPackageExists = errors.New("package already exists")
+	// ErrRevisionNotFound is returned when a package has no revision with the requested number.
+	ErrRevisionNotFound = errors.New("package revision not found")
 )
 
 type packageRepo struct {
-	db         *bolt.DB
-	bucketName string
+	db                 *bolt.DB
+	bucketName         string
+	revisionBucketName string
 }
 
 // Compile-time check to ensure that packageRepo{} implements the domain.PackageRepo interface.
@@ -33,8 +39,9 @@ func New(db *db.DB) (domain.PackageRepo, error) {
 	}
 
 	return &packageRepo{
-		db:         db.Core,
-		bucketName: (&domain.Package{}).Bucket(),
+		db:                 db.Core,
+		bucketName:         (&domain.Package{}).Bucket(),
+		revisionBucketName: (&domain.PackageRevision{}).Bucket(),
 	}, nil
 }
 
@@ -61,7 +68,7 @@ func (p packageRepo) Fetch(ctx context.Context) ([]domain.Package, error) {
 
 			// Unmarshal the package.
 			_package := domain.Package{}
-			if err := json.Unmarshal(pkgData, &_package); err != nil {
+			if err := unmarshalPackage(pkgData, &_package); err != nil {
 				return errors.Wrap(err, "unmarshal package")
 			}
 
@@ -98,7 +105,7 @@ func (p packageRepo) GetByLabel(ctx context.Context, label string) (domain.Packa
 		}
 
 		// Unmarshal the package.
-		if err := json.Unmarshal(pkgData, &_package); err != nil {
+		if err := unmarshalPackage(pkgData, &_package); err != nil {
 			return errors.Wrap(err, "unmarshal package")
 		}
 
@@ -108,6 +115,20 @@ func (p packageRepo) GetByLabel(ctx context.Context, label string) (domain.Packa
 	return _package, err
 }
 
+// unmarshalPackage unmarshals a stored package. Packages that were stored before revisions were introduced are treated
+// as their first revision.
+func unmarshalPackage(data []byte, _package *domain.Package) error {
+	if err := json.Unmarshal(data, _package); err != nil {
+		return err
+	}
+
+	if _package.Revision < 1 {
+		_package.Revision = 1
+	}
+
+	return nil
+}
+
 func (p packageRepo) doesPackageExist(ctx context.Context, label string) bool {
 	err := p.db.View(func(tx *bolt.Tx) error {
 		// Check if context is canceled.
@@ -152,22 +173,33 @@ func (p packageRepo) Store(ctx context.Context, _package *domain.PackageAdd) err
 			return ErrPackageExists
 		}
 
-		// Marshal the package.
+		// Marshal the package; this also sets its timestamps. Then set the initial revision.
 		pkgData, err := json.Marshal(_package)
 		if err != nil {
 			return errors.Wrap(err, "marshal package")
 		}
 
+		record := domain.Package{}
+		if err = json.Unmarshal(pkgData, &record); err != nil {
+			return errors.Wrap(err, "unmarshal package")
+		}
+		record.Revision = 1
+
+		if pkgData, err = json.Marshal(&record); err != nil {
+			return errors.Wrap(err, "marshal package")
+		}
+
 		// Store the package.
 		if err = bucket.Put([]byte(_package.Label), pkgData); err != nil {
 			return errors.Wrap(err, "store package")
 		}
 
-		return nil
+		return p.putRevision(tx, &record, _package.Source)
 	})
 }
 
-// Update updates a package in the database.
+// Update updates a package in the database. The stored package is replaced as a whole, except for its creation date;
+// its revision gets incremented.
 func (p packageRepo) Update(ctx context.Context, _package *domain.PackageUpdate) error {
 	// Update the package in the database.
 	return p.db.Update(func(tx *bolt.Tx) error {
@@ -182,23 +214,49 @@ func (p packageRepo) Update(ctx context.Context, _package *domain.PackageUpdate)
 			return db.ErrBucketNotFound
 		}
 
-		// Check if package exists.
-		if !p.doesPackageExist(ctx, _package.Label) {
+		// Load the stored package; its creation date and revision are carried over.
+		storedData := bucket.Get([]byte(_package.Label))
+		if storedData == nil {
 			return ErrPackageNotFound
 		}
 
-		// Marshal the package.
+		stored := domain.Package{}
+		if err := unmarshalPackage(storedData, &stored); err != nil {
+			return errors.Wrap(err, "unmarshal package")
+		}
+
+		// Packages that were stored before the revision history was introduced get their current revision recorded
+		// first, so that it can still be restored.
+		if !p.hasRevision(tx, stored.Label, stored.Revision) {
+			if err := p.putRevision(tx, &stored, ""); err != nil {
+				return err
+			}
+		}
+
+		// Marshal the package. Then carry over its creation date and bump its revision.
 		pkgData, err := json.Marshal(_package)
 		if err != nil {
 			return errors.Wrap(err, "marshal package")
 		}
 
+		record := &domain.Package{}
+		if err = json.Unmarshal(pkgData, record); err != nil {
+			return errors.Wrap(err, "unmarshal package")
+		}
+		record.Revision = stored.Revision + 1
+		record.CreatedAt = stored.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if pkgData, err = json.Marshal(record); err != nil {
+			return errors.Wrap(err, "marshal package")
+		}
+
 		// Store/update the package. This will overwrite the existing package. Comparable to a PUT.
 		if err = bucket.Put([]byte(_package.Label), pkgData); err != nil {
 			return errors.Wrap(err, "store package")
 		}
 
-		return nil
+		return p.putRevision(tx, record, _package.Source)
 	})
 }
 
@@ -227,6 +285,141 @@ func (p packageRepo) Remove(ctx context.Context, label string) error {
 			return errors.Wrap(err, "remove package")
 		}
 
+		// Remove the package's revisions; a package that gets installed again under the same label starts over.
+		revisions := tx.Bucket([]byte(p.revisionBucketName))
+		if revisions == nil || revisions.Bucket([]byte(label)) == nil {
+			return nil
+		}
+		if err := revisions.DeleteBucket([]byte(label)); err != nil {
+			return errors.Wrap(err, "remove package revisions")
+		}
+
 		return nil
 	})
 }
+
+// revisionKey returns the key of a revision. Keys are big endian encoded, so that revisions are sorted by their number.
+func revisionKey(revision int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(revision))
+
+	return key
+}
+
+// putRevision records the given package as a revision in the package's revision bucket.
+func (p packageRepo) putRevision(tx *bolt.Tx, _package *domain.Package, source string) error {
+	revisions, err := tx.CreateBucketIfNotExists([]byte(p.revisionBucketName))
+	if err != nil {
+		return errors.Wrap(err, "create revisions bucket")
+	}
+
+	bucket, err := revisions.CreateBucketIfNotExists([]byte(_package.Label))
+	if err != nil {
+		return errors.Wrapf(err, "create revisions bucket of package %q", _package.Label)
+	}
+
+	revisionData, err := json.Marshal(&domain.PackageRevision{
+		Revision:  _package.Revision,
+		Source:    source,
+		CreatedAt: _package.UpdatedAt,
+		Package:   _package,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal revision")
+	}
+
+	if err = bucket.Put(revisionKey(_package.Revision), revisionData); err != nil {
+		return errors.Wrap(err, "store revision")
+	}
+
+	return nil
+}
+
+// hasRevision checks whether the given revision of a package was recorded.
+func (p packageRepo) hasRevision(tx *bolt.Tx, label string, revision int) bool {
+	revisions := tx.Bucket([]byte(p.revisionBucketName))
+	if revisions == nil {
+		return false
+	}
+
+	bucket := revisions.Bucket([]byte(label))
+
+	return bucket != nil && bucket.Get(revisionKey(revision)) != nil
+}
+
+// History returns all revisions of a package, oldest first. The current definition of the package is always part of
+// the history; for packages that were stored before the history was introduced, it is the only revision.
+func (p packageRepo) History(ctx context.Context, label string) ([]domain.PackageRevision, error) {
+	var history []domain.PackageRevision
+	err := p.db.View(func(tx *bolt.Tx) error {
+		// Check if context is canceled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Load the current package; it has to exist for its history to be of interest.
+		bucket := tx.Bucket([]byte(p.bucketName))
+		if bucket == nil {
+			return db.ErrBucketNotFound
+		}
+
+		pkgData := bucket.Get([]byte(label))
+		if pkgData == nil {
+			return ErrPackageNotFound
+		}
+
+		current := domain.Package{}
+		if err := unmarshalPackage(pkgData, &current); err != nil {
+			return errors.Wrap(err, "unmarshal package")
+		}
+
+		// Load the recorded revisions.
+		if revisions := tx.Bucket([]byte(p.revisionBucketName)); revisions != nil {
+			if revisionBucket := revisions.Bucket([]byte(label)); revisionBucket != nil {
+				err := revisionBucket.ForEach(func(_, revisionData []byte) error {
+					revision := domain.PackageRevision{}
+					if err := json.Unmarshal(revisionData, &revision); err != nil {
+						return errors.Wrap(err, "unmarshal revision")
+					}
+
+					history = append(history, revision)
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if !p.hasRevision(tx, label, current.Revision) {
+			history = append(history, domain.PackageRevision{
+				Revision:  current.Revision,
+				CreatedAt: current.UpdatedAt,
+				Package:   &current,
+			})
+		}
+
+		sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+
+		return nil
+	})
+
+	return history, err
+}
+
+// GetRevision fetches a single revision of a package.
+func (p packageRepo) GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error) {
+	history, err := p.History(ctx, label)
+	if err != nil {
+		return domain.PackageRevision{}, err
+	}
+
+	for _, entry := range history {
+		if entry.Revision == revision {
+			return entry, nil
+		}
+	}
+
+	return domain.PackageRevision{}, ErrRevisionNotFound
+}
@@ -0,0 +1,208 @@
+package bolt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	db "github.com/nikoksr/proji/pkg/database/bolt"
+	"github.com/nikoksr/proji/pkg/pointer"
+)
+
+func newTestRepo(t *testing.T) (*packageRepo, func()) {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "proji_test_*")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+
+	database, err := db.Connect(context.Background(), filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+
+	repo, err := New(database)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	return repo.(*packageRepo), func() {
+		_ = database.Close(context.Background())
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestPackageRepo_Revisions(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := repo.Store(ctx, domain.NewPackage("test", "tst")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	stored, err := repo.GetByLabel(ctx, "tst")
+	if err != nil {
+		t.Fatalf("GetByLabel() failed: %v", err)
+	}
+	if stored.Revision != 1 {
+		t.Fatalf("expected revision 1 after store, got %d", stored.Revision)
+	}
+
+	// Storing a package with the same label again has to fail.
+	if err = repo.Store(ctx, domain.NewPackage("test", "tst")); !errors.Is(err, ErrPackageExists) {
+		t.Fatalf("Store() error = %v, want %v", err, ErrPackageExists)
+	}
+
+	// Updates replace the package, keep its creation date and bump its revision.
+	update := stored.AsUpdatable()
+	update.Name = "Test"
+	update.Description = pointer.To("Some description.")
+	if err = repo.Update(ctx, update); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	updated, err := repo.GetByLabel(ctx, "tst")
+	if err != nil {
+		t.Fatalf("GetByLabel() failed: %v", err)
+	}
+	if updated.Revision != 2 {
+		t.Fatalf("expected revision 2 after update, got %d", updated.Revision)
+	}
+	if updated.Name != "Test" || updated.Description == nil || *updated.Description != "Some description." {
+		t.Fatalf("update was not applied: %+v", updated)
+	}
+	if !updated.CreatedAt.Equal(stored.CreatedAt) {
+		t.Fatalf("expected creation date %v to be preserved, got %v", stored.CreatedAt, updated.CreatedAt)
+	}
+
+	// Updating an unknown package has to fail.
+	if err = repo.Update(ctx, &domain.PackageUpdate{Label: "xxx"}); !errors.Is(err, ErrPackageNotFound) {
+		t.Fatalf("Update() error = %v, want %v", err, ErrPackageNotFound)
+	}
+}
+
+func TestPackageRepo_LegacyRevision(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	// Packages that were stored before revisions were introduced have no revision at all.
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(repo.bucketName))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte("tst"), []byte(`{"label":"tst","name":"test"}`))
+	})
+	if err != nil {
+		t.Fatalf("failed to store legacy package: %v", err)
+	}
+
+	_package, err := repo.GetByLabel(context.Background(), "tst")
+	if err != nil {
+		t.Fatalf("GetByLabel() failed: %v", err)
+	}
+	if _package.Revision != 1 {
+		t.Fatalf("expected legacy package to be at revision 1, got %d", _package.Revision)
+	}
+
+	// Without any recorded revisions, the history consists of the current definition.
+	history, err := repo.History(context.Background(), "tst")
+	if err != nil {
+		t.Fatalf("History() failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Revision != 1 || history[0].Package.Name != "test" {
+		t.Fatalf("unexpected history of legacy package: %+v", history)
+	}
+
+	// Updating a legacy package keeps its current definition as the first revision.
+	update := _package.AsUpdatable()
+	update.Name = "Test"
+	if err = repo.Update(context.Background(), update); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	first, err := repo.GetRevision(context.Background(), "tst", 1)
+	if err != nil {
+		t.Fatalf("GetRevision() failed: %v", err)
+	}
+	if first.Package.Name != "test" {
+		t.Fatalf("expected revision 1 to keep the legacy definition, got %q", first.Package.Name)
+	}
+}
+
+func TestPackageRepo_History(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := repo.History(ctx, "tst"); !errors.Is(err, db.ErrBucketNotFound) {
+		t.Fatalf("History() error = %v, want %v", err, db.ErrBucketNotFound)
+	}
+
+	_package := domain.NewPackage("test", "tst")
+	_package.Source = "tst.toml"
+	if err := repo.Store(ctx, _package); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	for _, name := range []string{"Test", "Tested"} {
+		update := &domain.PackageUpdate{Label: "tst", Name: name, Source: "edit"}
+		if err := repo.Update(ctx, update); err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+	}
+
+	history, err := repo.History(ctx, "tst")
+	if err != nil {
+		t.Fatalf("History() failed: %v", err)
+	}
+
+	wantNames := []string{"test", "Test", "Tested"}
+	wantSources := []string{"tst.toml", "edit", "edit"}
+	if len(history) != len(wantNames) {
+		t.Fatalf("History() returned %d revisions, want %d", len(history), len(wantNames))
+	}
+	for idx, revision := range history {
+		if revision.Revision != idx+1 {
+			t.Errorf("revision #%d has number %d, want %d", idx, revision.Revision, idx+1)
+		}
+		if revision.Package.Name != wantNames[idx] || revision.Source != wantSources[idx] {
+			t.Errorf("revision %d = (%q, %q), want (%q, %q)",
+				revision.Revision, revision.Package.Name, revision.Source, wantNames[idx], wantSources[idx])
+		}
+		if revision.CreatedAt.IsZero() {
+			t.Errorf("revision %d has no creation date", revision.Revision)
+		}
+	}
+
+	if _, err = repo.GetRevision(ctx, "tst", 4); !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("GetRevision() error = %v, want %v", err, ErrRevisionNotFound)
+	}
+
+	// Removing a package removes its history as well.
+	if err = repo.Remove(ctx, "tst"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if err = repo.Store(ctx, domain.NewPackage("test", "tst")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+	if history, err = repo.History(ctx, "tst"); err != nil || len(history) != 1 {
+		t.Fatalf("History() = %d revisions, %v; want 1 revision after reinstalling", len(history), err)
+	}
+}
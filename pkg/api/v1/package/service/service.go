@@ -82,3 +82,13 @@ func (p packageService) UpdateFromUpstream(ctx context.Context, _package *domain
 func (p packageService) Remove(ctx context.Context, id string) error {
 	return p.packageRepo.Remove(ctx, id)
 }
+
+// History fetches all revisions of a package from the repository, oldest first.
+func (p packageService) History(ctx context.Context, label string) ([]domain.PackageRevision, error) {
+	return p.packageRepo.History(ctx, label)
+}
+
+// GetRevision fetches a single revision of a package from the repository.
+func (p packageService) GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error) {
+	return p.packageRepo.GetRevision(ctx, label, revision)
+}
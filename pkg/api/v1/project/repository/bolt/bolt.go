@@ -75,40 +75,54 @@ func (p projectRepo) Fetch(ctx context.Context) ([]domain.Project, error) {
 	return projects, err
 }
 
-// GetByID fetches a project from the database by id.
-func (p projectRepo) GetByID(ctx context.Context, id string) (domain.Project, error) {
-	// Call project from database by its id.
-	var project domain.Project
-	err := p.db.View(func(tx *bolt.Tx) error {
-		// Check if context is canceled.
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
+// findKey returns the key under which the project with the given id is stored. Projects are stored by their id.
+// Older versions of proji stored projects by their path though, so if no project is stored under the given id, the
+// bucket gets scanned for a project with a matching id. It returns nil if the project does not exist.
+func findKey(bucket *bolt.Bucket, id string) ([]byte, error) {
+	if bucket.Get([]byte(id)) != nil {
+		return []byte(id), nil
+	}
 
-		// Open the bucket.
-		bucket := tx.Bucket([]byte(p.bucketName))
-		if bucket == nil {
-			return db.ErrBucketNotFound
+	var key []byte
+	err := bucket.ForEach(func(k, projectData []byte) error {
+		project := domain.Project{}
+		if err := json.Unmarshal(projectData, &project); err != nil {
+			return errors.Wrap(err, "unmarshal project")
 		}
 
-		// Get the project data.
-		projectData := bucket.Get([]byte(id))
-		if projectData == nil {
-			return ErrProjectNotFound
+		if project.ID == id {
+			key = k
 		}
 
-		// Unmarshal the project.
+		return nil
+	})
+
+	return key, err
+}
+
+// isPathTaken checks whether a project with the given path is already stored in the bucket.
+func isPathTaken(bucket *bolt.Bucket, path string) (bool, error) {
+	taken := false
+	err := bucket.ForEach(func(_, projectData []byte) error {
+		project := domain.Project{}
 		if err := json.Unmarshal(projectData, &project); err != nil {
 			return errors.Wrap(err, "unmarshal project")
 		}
 
+		if project.Path == path {
+			taken = true
+		}
+
 		return nil
 	})
 
-	return project, err
+	return taken, err
 }
 
-func (p projectRepo) doesProjectExist(ctx context.Context, path string) bool {
+// GetByID fetches a project from the database by id.
+func (p projectRepo) GetByID(ctx context.Context, id string) (domain.Project, error) {
+	// Call project from database by its id.
+	var project domain.Project
 	err := p.db.View(func(tx *bolt.Tx) error {
 		// Check if context is canceled.
 		if ctx.Err() != nil {
@@ -122,14 +136,23 @@ func (p projectRepo) doesProjectExist(ctx context.Context, path string) bool {
 		}
 
 		// Get the project data.
-		if bucket.Get([]byte(path)) == nil {
+		key, err := findKey(bucket, id)
+		if err != nil {
+			return errors.Wrap(err, "find project")
+		}
+		if key == nil {
 			return ErrProjectNotFound
 		}
 
+		// Unmarshal the project.
+		if err := json.Unmarshal(bucket.Get(key), &project); err != nil {
+			return errors.Wrap(err, "unmarshal project")
+		}
+
 		return nil
 	})
 
-	return err == nil
+	return project, err
 }
 
 // Store stores a project in the database.
@@ -147,19 +170,23 @@ func (p projectRepo) Store(ctx context.Context, project *domain.ProjectAdd) erro
 			return errors.Wrap(err, "create bucket")
 		}
 
-		// Marshal the project.
-		projectData, err := json.Marshal(project)
+		// Check if a project with the same path already exists.
+		taken, err := isPathTaken(bucket, project.Path)
 		if err != nil {
-			return errors.Wrap(err, "marshal project")
+			return errors.Wrap(err, "check for existing project")
 		}
-
-		// Check if project already exists.
-		if p.doesProjectExist(ctx, project.Path) {
+		if taken {
 			return ErrProjectExists
 		}
 
+		// Marshal the project. This also assigns an id to the project, if it has none yet.
+		projectData, err := json.Marshal(project)
+		if err != nil {
+			return errors.Wrap(err, "marshal project")
+		}
+
 		// Store the project.
-		if err = bucket.Put([]byte(project.Path), projectData); err != nil {
+		if err = bucket.Put([]byte(project.ID), projectData); err != nil {
 			return errors.Wrap(err, "store project")
 		}
 
@@ -167,8 +194,8 @@ func (p projectRepo) Store(ctx context.Context, project *domain.ProjectAdd) erro
 	})
 }
 
-// Update updates a project in the database.
-func (p projectRepo) Update(ctx context.Context, project *domain.ProjectUpdate) error {
+// Update updates a project in the database. Only the non-empty fields of the update are applied to the stored project.
+func (p projectRepo) Update(ctx context.Context, update *domain.ProjectUpdate) error {
 	// Update the project in the database.
 	return p.db.Update(func(tx *bolt.Tx) error {
 		// Check if context is canceled.
@@ -182,19 +209,31 @@ func (p projectRepo) Update(ctx context.Context, project *domain.ProjectUpdate)
 			return db.ErrBucketNotFound
 		}
 
-		// Check if project already exists.
-		if !p.doesProjectExist(ctx, project.ID) {
+		// Check if project exists.
+		key, err := findKey(bucket, update.ID)
+		if err != nil {
+			return errors.Wrap(err, "find project")
+		}
+		if key == nil {
 			return ErrProjectNotFound
 		}
 
+		// Load the stored project and apply the update to it.
+		project := domain.Project{}
+		if err = json.Unmarshal(bucket.Get(key), &project); err != nil {
+			return errors.Wrap(err, "unmarshal project")
+		}
+
+		project.ApplyUpdate(update)
+
 		// Marshal the project.
-		projectData, err := json.Marshal(project)
+		projectData, err := json.Marshal(&project)
 		if err != nil {
 			return errors.Wrap(err, "marshal project")
 		}
 
 		// Store/update the project. This will overwrite the existing project. Comparable to a PUT.
-		if err = bucket.Put([]byte(project.ID), projectData); err != nil {
+		if err = bucket.Put(key, projectData); err != nil {
 			return errors.Wrap(err, "store project")
 		}
 
@@ -218,12 +257,16 @@ func (p projectRepo) Remove(ctx context.Context, id string) error {
 		}
 
 		// Check if project exists.
-		if !p.doesProjectExist(ctx, id) {
+		key, err := findKey(bucket, id)
+		if err != nil {
+			return errors.Wrap(err, "find project")
+		}
+		if key == nil {
 			return ErrProjectNotFound
 		}
 
 		// Remove the project.
-		if err := bucket.Delete([]byte(id)); err != nil {
+		if err = bucket.Delete(key); err != nil {
 			return errors.Wrap(err, "remove project")
 		}
 
@@ -0,0 +1,144 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	db "github.com/nikoksr/proji/pkg/database/bolt"
+)
+
+func newTestRepo(t *testing.T) (*projectRepo, func()) {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "proji_test_*")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+
+	database, err := db.Connect(context.Background(), filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+
+	repo, err := New(database)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	return repo.(*projectRepo), func() {
+		_ = database.Close(context.Background())
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestProjectRepo_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	project := domain.NewProject("tst", "/some/where", "test")
+	if err := repo.Store(ctx, project); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+	if project.ID == "" {
+		t.Fatal("Store() did not assign an id to the project")
+	}
+
+	// Storing another project with the same path has to fail.
+	err := repo.Store(ctx, domain.NewProject("tst", "/some/where", "other"))
+	if !errors.Is(err, ErrProjectExists) {
+		t.Fatalf("Store() error = %v, want %v", err, ErrProjectExists)
+	}
+
+	// Update has to keep all fields that are not part of the update.
+	err = repo.Update(ctx, &domain.ProjectUpdate{ID: project.ID, Name: "renamed"})
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("GetByID() failed: %v", err)
+	}
+	if got.Name != "renamed" {
+		t.Fatalf("GetByID() name = %q, want %q", got.Name, "renamed")
+	}
+	if got.Path != project.Path || got.Package != project.Package {
+		t.Fatalf("Update() lost fields; got path %q and package %q", got.Path, got.Package)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Fatal("Update() lost the creation date")
+	}
+
+	if err = repo.Remove(ctx, project.ID); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if _, err = repo.GetByID(ctx, project.ID); !errors.Is(err, ErrProjectNotFound) {
+		t.Fatalf("GetByID() error = %v, want %v", err, ErrProjectNotFound)
+	}
+}
+
+func TestProjectRepo_LegacyPathKeys(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Older versions of proji stored projects by their path.
+	legacy := domain.Project{ID: "legacy", Path: "/some/where", Name: "test", Package: "tst"}
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(repo.bucketName))
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(legacy)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(legacy.Path), data)
+	})
+	if err != nil {
+		t.Fatalf("failed to store legacy project: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, legacy.ID)
+	if err != nil {
+		t.Fatalf("GetByID() failed: %v", err)
+	}
+	if got.Path != legacy.Path {
+		t.Fatalf("GetByID() path = %q, want %q", got.Path, legacy.Path)
+	}
+
+	if err = repo.Update(ctx, &domain.ProjectUpdate{ID: legacy.ID, Name: "renamed"}); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	projects, err := repo.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() failed: %v", err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("Fetch() returned %d projects, want 1", len(projects))
+	}
+	if projects[0].Name != "renamed" {
+		t.Fatalf("Fetch() name = %q, want %q", projects[0].Name, "renamed")
+	}
+
+	if err = repo.Remove(ctx, legacy.ID); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+}
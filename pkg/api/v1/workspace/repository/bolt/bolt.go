@@ -0,0 +1,218 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	db "github.com/nikoksr/proji/pkg/database/bolt"
+)
+
+var (
+	// ErrWorkspaceNotFound is returned when a workspace is not found in the repository.
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceExists is returned when a workspace with the same name already exists in the repository.
+	ErrWorkspaceExists = errors.New("workspace already exists")
+)
+
+type workspaceRepo struct {
+	db         *bolt.DB
+	bucketName string
+}
+
+// Compile-time check to ensure that workspaceRepo implements the domain.WorkspaceRepo interface.
+var _ domain.WorkspaceRepo = (*workspaceRepo)(nil)
+
+// New returns a new instance of the workspace repository. It requires a bolt database.
+func New(db *db.DB) (domain.WorkspaceRepo, error) {
+	if db == nil || db.Core == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &workspaceRepo{
+		db:         db.Core,
+		bucketName: domain.Workspace{}.Bucket(),
+	}, nil
+}
+
+// Fetch fetches all workspaces from the database.
+func (w workspaceRepo) Fetch(ctx context.Context) ([]domain.Workspace, error) {
+	// Call workspaces from the database.
+	var workspaces []domain.Workspace
+	err := w.db.View(func(tx *bolt.Tx) error {
+		// Open the bucket.
+		bucket := tx.Bucket([]byte(w.bucketName))
+		if bucket == nil {
+			return db.ErrBucketNotFound
+		}
+
+		// Pre-alloc the workspace list.
+		workspaces = make([]domain.Workspace, 0, bucket.Stats().KeyN)
+
+		// Iterate over the bucket. Keys are sorted, so the workspaces are returned in order of their names.
+		return bucket.ForEach(func(_, workspaceData []byte) error {
+			// Check if context is canceled.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// Unmarshal the workspace.
+			workspace := domain.Workspace{}
+			if err := json.Unmarshal(workspaceData, &workspace); err != nil {
+				return errors.Wrap(err, "unmarshal workspace")
+			}
+
+			// Add the workspace to the list.
+			workspaces = append(workspaces, workspace)
+
+			return nil
+		})
+	})
+
+	return workspaces, err
+}
+
+// GetByName fetches a workspace from the database by name.
+func (w workspaceRepo) GetByName(ctx context.Context, name string) (domain.Workspace, error) {
+	// Call workspace from database by its name.
+	var workspace domain.Workspace
+	err := w.db.View(func(tx *bolt.Tx) error {
+		// Check if context is canceled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Open the bucket.
+		bucket := tx.Bucket([]byte(w.bucketName))
+		if bucket == nil {
+			return db.ErrBucketNotFound
+		}
+
+		// Get the workspace data.
+		workspaceData := bucket.Get([]byte(name))
+		if workspaceData == nil {
+			return ErrWorkspaceNotFound
+		}
+
+		// Unmarshal the workspace.
+		if err := json.Unmarshal(workspaceData, &workspace); err != nil {
+			return errors.Wrap(err, "unmarshal workspace")
+		}
+
+		return nil
+	})
+
+	return workspace, err
+}
+
+// Store stores a workspace in the database.
+func (w workspaceRepo) Store(ctx context.Context, workspace *domain.WorkspaceAdd) error {
+	// Store the workspace in the database.
+	return w.db.Update(func(tx *bolt.Tx) error {
+		// Check if context is canceled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Open the bucket.
+		bucket, err := tx.CreateBucketIfNotExists([]byte(w.bucketName))
+		if err != nil {
+			return errors.Wrap(err, "create bucket")
+		}
+
+		// Check if a workspace with the same name already exists.
+		if bucket.Get([]byte(workspace.Name)) != nil {
+			return ErrWorkspaceExists
+		}
+
+		// Marshal the workspace.
+		workspaceData, err := json.Marshal(workspace)
+		if err != nil {
+			return errors.Wrap(err, "marshal workspace")
+		}
+
+		// Store the workspace.
+		if err = bucket.Put([]byte(workspace.Name), workspaceData); err != nil {
+			return errors.Wrap(err, "store workspace")
+		}
+
+		return nil
+	})
+}
+
+// Update updates a workspace in the database. Only the non-empty fields of the update are applied to the stored
+// workspace.
+func (w workspaceRepo) Update(ctx context.Context, update *domain.WorkspaceUpdate) error {
+	// Update the workspace in the database.
+	return w.db.Update(func(tx *bolt.Tx) error {
+		// Check if context is canceled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Open the bucket.
+		bucket := tx.Bucket([]byte(w.bucketName))
+		if bucket == nil {
+			return db.ErrBucketNotFound
+		}
+
+		// Check if workspace exists.
+		workspaceData := bucket.Get([]byte(update.Name))
+		if workspaceData == nil {
+			return ErrWorkspaceNotFound
+		}
+
+		// Load the stored workspace and apply the update to it.
+		workspace := domain.Workspace{}
+		if err := json.Unmarshal(workspaceData, &workspace); err != nil {
+			return errors.Wrap(err, "unmarshal workspace")
+		}
+
+		workspace.ApplyUpdate(update)
+
+		// Marshal the workspace.
+		workspaceData, err := json.Marshal(&workspace)
+		if err != nil {
+			return errors.Wrap(err, "marshal workspace")
+		}
+
+		// Store/update the workspace. This will overwrite the existing workspace. Comparable to a PUT.
+		if err = bucket.Put([]byte(update.Name), workspaceData); err != nil {
+			return errors.Wrap(err, "store workspace")
+		}
+
+		return nil
+	})
+}
+
+// Remove removes a workspace from the database. The member projects are not touched.
+func (w workspaceRepo) Remove(ctx context.Context, name string) error {
+	// Remove the workspace from the database.
+	return w.db.Update(func(tx *bolt.Tx) error {
+		// Check if context is canceled.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Open the bucket.
+		bucket := tx.Bucket([]byte(w.bucketName))
+		if bucket == nil {
+			return db.ErrBucketNotFound
+		}
+
+		// Check if workspace exists.
+		if bucket.Get([]byte(name)) == nil {
+			return ErrWorkspaceNotFound
+		}
+
+		// Remove the workspace.
+		if err := bucket.Delete([]byte(name)); err != nil {
+			return errors.Wrap(err, "remove workspace")
+		}
+
+		return nil
+	})
+}
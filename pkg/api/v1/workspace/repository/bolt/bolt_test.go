@@ -0,0 +1,104 @@
+package bolt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	db "github.com/nikoksr/proji/pkg/database/bolt"
+)
+
+func newTestRepo(t *testing.T) (*workspaceRepo, func()) {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "proji_test_*")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+
+	database, err := db.Connect(context.Background(), filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+
+	repo, err := New(database)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	return repo.(*workspaceRepo), func() {
+		_ = database.Close(context.Background())
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestWorkspaceRepo_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	repo, cleanup := newTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Fetching from an empty database has to report the missing bucket.
+	if _, err := repo.Fetch(ctx); !errors.Is(err, db.ErrBucketNotFound) {
+		t.Fatalf("Fetch() error = %v, want %v", err, db.ErrBucketNotFound)
+	}
+
+	workspace := domain.NewWorkspace("shop", "/code/shop")
+	if err := repo.Store(ctx, workspace); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	// Storing another workspace with the same name has to fail.
+	err := repo.Store(ctx, domain.NewWorkspace("shop", "/code/other"))
+	if !errors.Is(err, ErrWorkspaceExists) {
+		t.Fatalf("Store() error = %v, want %v", err, ErrWorkspaceExists)
+	}
+
+	err = repo.Update(ctx, &domain.WorkspaceUpdate{Name: "shop", AddProjects: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	err = repo.Update(ctx, &domain.WorkspaceUpdate{Name: "shop", RemoveProjects: []string{"a"}})
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	got, err := repo.GetByName(ctx, "shop")
+	if err != nil {
+		t.Fatalf("GetByName() failed: %v", err)
+	}
+	if got.Root != "/code/shop" {
+		t.Fatalf("GetByName() root = %q, want %q", got.Root, "/code/shop")
+	}
+	if len(got.Projects) != 1 || got.Projects[0] != "b" {
+		t.Fatalf("GetByName() projects = %v, want [b]", got.Projects)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Fatal("Update() lost the creation date")
+	}
+
+	if err = repo.Update(ctx, &domain.WorkspaceUpdate{Name: "missing"}); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("Update() error = %v, want %v", err, ErrWorkspaceNotFound)
+	}
+
+	workspaces, err := repo.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() failed: %v", err)
+	}
+	if len(workspaces) != 1 {
+		t.Fatalf("Fetch() returned %d workspaces, want 1", len(workspaces))
+	}
+
+	if err = repo.Remove(ctx, "shop"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if _, err = repo.GetByName(ctx, "shop"); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("GetByName() error = %v, want %v", err, ErrWorkspaceNotFound)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+type workspaceService struct {
+	timeout       time.Duration
+	workspaceRepo domain.WorkspaceRepo
+}
+
+// Compile-time check to ensure that workspaceService implements the domain.WorkspaceService interface.
+var _ domain.WorkspaceService = (*workspaceService)(nil)
+
+// New returns a new instance of the workspace service. It requires a workspace repository.
+func New(timeout time.Duration, repo domain.WorkspaceRepo) (domain.WorkspaceService, error) {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	if repo == nil {
+		return nil, errors.New("workspace repository is nil")
+	}
+
+	return &workspaceService{
+		timeout:       timeout,
+		workspaceRepo: repo,
+	}, nil
+}
+
+// Fetch fetches all workspaces from the repository.
+func (w workspaceService) Fetch(ctx context.Context) ([]domain.Workspace, error) {
+	return w.workspaceRepo.Fetch(ctx)
+}
+
+// GetByName fetches a workspace from the repository by name.
+func (w workspaceService) GetByName(ctx context.Context, name string) (domain.Workspace, error) {
+	return w.workspaceRepo.GetByName(ctx, name)
+}
+
+// Store stores a workspace in the repository.
+func (w workspaceService) Store(ctx context.Context, workspace *domain.WorkspaceAdd) error {
+	return w.workspaceRepo.Store(ctx, workspace)
+}
+
+// Update updates a workspace in the repository.
+func (w workspaceService) Update(ctx context.Context, workspace *domain.WorkspaceUpdate) error {
+	return w.workspaceRepo.Update(ctx, workspace)
+}
+
+// Remove removes a workspace from the repository.
+func (w workspaceService) Remove(ctx context.Context, name string) error {
+	return w.workspaceRepo.Remove(ctx, name)
+}
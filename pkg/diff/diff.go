@@ -0,0 +1,225 @@
+// Package diff implements line based diffs and three-way merges of text files. The implementation is based on the
+// longest common subsequence of lines and is meant for reasonably small files, like the ones generated from templates.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind is the kind of an edit operation.
+type OpKind int
+
+const (
+	OpEqual  OpKind = iota // The line is part of both inputs
+	OpDelete               // The line is only part of the first input
+	OpInsert               // The line is only part of the second input
+)
+
+// Edit is a single line of an edit script that transforms one input into another.
+type Edit struct {
+	Kind OpKind
+	Text string // The line, including its line ending
+}
+
+// SplitLines splits text into lines. Line endings are kept, so that joining the lines results in the original text.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// lcs returns, for each line of a, the index of the matching line in b or -1 if the line is not part of the longest
+// common subsequence of a and b.
+func lcs(a, b []string) []int {
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	// Common prefix and suffix are matched upfront; this keeps the table small for typical, local changes.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		matches[prefix] = prefix
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		matches[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a) == 0 || len(b) == 0 {
+		return matches
+	}
+
+	// lengths[i][j] holds the length of the longest common subsequence of a[i:] and b[j:].
+	lengths := make([][]int32, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			matches[prefix+i] = prefix + j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
+// Lines returns the edit script that transforms the lines of a into the lines of b.
+func Lines(a, b []string) []Edit {
+	matches := lcs(a, b)
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	j := 0
+	for i, line := range a {
+		if matches[i] < 0 {
+			edits = append(edits, Edit{Kind: OpDelete, Text: line})
+			continue
+		}
+
+		for ; j < matches[i]; j++ {
+			edits = append(edits, Edit{Kind: OpInsert, Text: b[j]})
+		}
+
+		edits = append(edits, Edit{Kind: OpEqual, Text: line})
+		j++
+	}
+
+	for ; j < len(b); j++ {
+		edits = append(edits, Edit{Kind: OpInsert, Text: b[j]})
+	}
+
+	return edits
+}
+
+// hunk is a range of edits that gets printed as a single unit of a unified diff.
+type hunk struct {
+	start, end       int // Range of edits
+	aStart, bStart   int // First line of the hunk in a and b; zero based
+	aLength, bLength int
+}
+
+// Unified returns the unified diff of a and b. The names are used in the header of the diff. Each change is surrounded
+// by up to context lines of unchanged text. An empty string is returned if a and b are equal.
+func Unified(aName, bName, a, b string, context int) string {
+	edits := Lines(SplitLines(a), SplitLines(b))
+
+	// Collect hunks; changes that are less than two contexts apart get merged into a single hunk.
+	var hunks []*hunk
+	var current *hunk
+	aLine, bLine, lastChange := 0, 0, -1
+	for idx, edit := range edits {
+		if edit.Kind != OpEqual {
+			if current == nil || idx-lastChange-1 > 2*context {
+				start := idx - context
+				if start < 0 {
+					start = 0
+				}
+
+				// Count back the lines of the leading context
+				leading := idx - start
+				current = &hunk{start: start, aStart: aLine - leading, bStart: bLine - leading}
+				hunks = append(hunks, current)
+			}
+
+			lastChange = idx
+			current.end = idx + 1
+		}
+
+		switch edit.Kind {
+		case OpEqual:
+			aLine++
+			bLine++
+		case OpDelete:
+			aLine++
+		case OpInsert:
+			bLine++
+		}
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+
+	for _, h := range hunks {
+		// Add the trailing context
+		h.end += context
+		if h.end > len(edits) {
+			h.end = len(edits)
+		}
+
+		var body strings.Builder
+		for _, edit := range edits[h.start:h.end] {
+			prefix := " "
+			switch edit.Kind {
+			case OpEqual:
+				h.aLength++
+				h.bLength++
+			case OpDelete:
+				prefix = "-"
+				h.aLength++
+			case OpInsert:
+				prefix = "+"
+				h.bLength++
+			}
+
+			body.WriteString(prefix + edit.Text)
+			if !strings.HasSuffix(edit.Text, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(h.aStart, h.aLength), hunkRange(h.bStart, h.bLength))
+		out.WriteString(body.String())
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk as expected by the unified diff format. Lines are one based; empty ranges
+// point at the line before the range.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
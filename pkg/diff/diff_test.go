@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitLines(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "single line without line break", text: "a", want: []string{"a"}},
+		{name: "lines", text: "a\nb\n", want: []string{"a\n", "b\n"}},
+		{name: "last line without line break", text: "a\nb", want: []string{"a\n", "b"}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := SplitLines(tc.text)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("SplitLines() mismatch (-want +got):\n%s", diff)
+			}
+			if strings.Join(got, "") != tc.text {
+				t.Fatalf("joined lines %q don't match text %q", strings.Join(got, ""), tc.text)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	got := Lines(SplitLines("a\nb\nc\nd\n"), SplitLines("a\nc\nx\nd\n"))
+	want := []Edit{
+		{Kind: OpEqual, Text: "a\n"},
+		{Kind: OpDelete, Text: "b\n"},
+		{Kind: OpEqual, Text: "c\n"},
+		{Kind: OpInsert, Text: "x\n"},
+		{Kind: OpEqual, Text: "d\n"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Lines() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "single change",
+			a:       "a\nb\nc\n",
+			b:       "a\nx\nc\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name:    "separate hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n",
+			b:       "x\n2\n3\n4\n5\n6\ny\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-1\n+x\n 2\n@@ -6,2 +6,2 @@\n 6\n-7\n+y\n",
+		},
+		{
+			name:    "insert into empty",
+			a:       "",
+			b:       "a\n",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:    "missing line break",
+			a:       "a",
+			b:       "b",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Unified("a", "b", tc.a, tc.b, tc.context)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Unified() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
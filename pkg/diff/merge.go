@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"strings"
+)
+
+// Conflict markers as used by git.
+const (
+	markerCurrent = "<<<<<<<"
+	markerBase    = "|||||||"
+	markerSplit   = "======="
+	markerNext    = ">>>>>>>"
+)
+
+// MergeResult is the result of a three-way merge.
+type MergeResult struct {
+	Text      string // The merged text; contains conflict markers if Conflicts is greater than zero
+	Conflicts int    // The number of conflicting regions
+}
+
+// chunk is a range of lines in base, current and next.
+type chunk struct {
+	base, current, next []string
+}
+
+// equalLines checks whether the two slices hold the same lines.
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Merge performs a three-way merge of current and next, both derived from base. Changes that were only made on one
+// side are applied; regions that were changed differently on both sides are written as conflicts, surrounded by git
+// style conflict markers. The labels are used to annotate the sides of a conflict.
+func Merge(base, current, next, currentLabel, nextLabel string) *MergeResult {
+	baseLines := SplitLines(base)
+	currentLines := SplitLines(current)
+	nextLines := SplitLines(next)
+
+	currentMatches := lcs(baseLines, currentLines)
+	nextMatches := lcs(baseLines, nextLines)
+
+	result := &MergeResult{}
+	var out strings.Builder
+
+	iBase, iCurrent, iNext := 0, 0, 0
+	for {
+		// Find the next line of base that is unchanged on both sides; everything in between is an unstable chunk.
+		sync := iBase
+		for sync < len(baseLines) && (currentMatches[sync] < 0 || nextMatches[sync] < 0) {
+			sync++
+		}
+
+		endCurrent, endNext := len(currentLines), len(nextLines)
+		if sync < len(baseLines) {
+			endCurrent, endNext = currentMatches[sync], nextMatches[sync]
+		}
+
+		if sync > iBase || endCurrent > iCurrent || endNext > iNext {
+			c := &chunk{
+				base:    baseLines[iBase:sync],
+				current: currentLines[iCurrent:endCurrent],
+				next:    nextLines[iNext:endNext],
+			}
+			if !mergeChunk(&out, c, currentLabel, nextLabel) {
+				result.Conflicts++
+			}
+		}
+
+		if sync >= len(baseLines) {
+			break
+		}
+
+		// Stable line
+		out.WriteString(baseLines[sync])
+		iBase, iCurrent, iNext = sync+1, endCurrent+1, endNext+1
+	}
+
+	result.Text = out.String()
+
+	return result
+}
+
+// mergeChunk writes the merged lines of the chunk. It returns false if both sides changed the chunk differently, in
+// which case a conflict gets written.
+func mergeChunk(out *strings.Builder, c *chunk, currentLabel, nextLabel string) bool {
+	switch {
+	case equalLines(c.current, c.next), equalLines(c.base, c.next):
+		writeLines(out, c.current)
+		return true
+	case equalLines(c.base, c.current):
+		writeLines(out, c.next)
+		return true
+	}
+
+	out.WriteString(markerCurrent + " " + currentLabel + "\n")
+	writeTerminatedLines(out, c.current)
+	out.WriteString(markerBase + " base\n")
+	writeTerminatedLines(out, c.base)
+	out.WriteString(markerSplit + "\n")
+	writeTerminatedLines(out, c.next)
+	out.WriteString(markerNext + " " + nextLabel + "\n")
+
+	return false
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeTerminatedLines writes the lines and makes sure that the last one ends with a line break, so that conflict
+// markers always start on a line of their own.
+func writeTerminatedLines(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	base := "a\nb\nc\nd\ne\n"
+
+	cases := []struct {
+		name          string
+		current, next string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:    "unchanged",
+			current: base,
+			next:    base,
+			want:    base,
+		},
+		{
+			name:    "only current changed",
+			current: "a\nB\nc\nd\ne\n",
+			next:    base,
+			want:    "a\nB\nc\nd\ne\n",
+		},
+		{
+			name:    "only next changed",
+			current: base,
+			next:    "a\nb\nc\nd\ne\nf\n",
+			want:    "a\nb\nc\nd\ne\nf\n",
+		},
+		{
+			name:    "both changed different regions",
+			current: "a\nB\nc\nd\ne\n",
+			next:    "a\nb\nc\nD\ne\n",
+			want:    "a\nB\nc\nD\ne\n",
+		},
+		{
+			name:    "both changed the same way",
+			current: "a\nX\nc\nd\ne\n",
+			next:    "a\nX\nc\nd\ne\n",
+			want:    "a\nX\nc\nd\ne\n",
+		},
+		{
+			name:          "conflict",
+			current:       "a\nB\nc\nd\ne\n",
+			next:          "a\nX\nc\nd\ne\n",
+			want:          "a\n<<<<<<< current\nB\n||||||| base\nb\n=======\nX\n>>>>>>> next\nc\nd\ne\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "conflict at the end without line break",
+			current:       "a\nb\nc\nd\nE",
+			next:          "a\nb\nc\nd\nF",
+			want:          "a\nb\nc\nd\n<<<<<<< current\nE\n||||||| base\ne\n=======\nF\n>>>>>>> next\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Merge(base, tc.current, tc.next, "current", "next")
+			if diff := cmp.Diff(tc.want, got.Text); diff != "" {
+				t.Fatalf("Merge() mismatch (-want +got):\n%s", diff)
+			}
+			if got.Conflicts != tc.wantConflicts {
+				t.Fatalf("expected %d conflicts, got %d", tc.wantConflicts, got.Conflicts)
+			}
+		})
+	}
+}
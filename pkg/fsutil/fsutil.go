@@ -0,0 +1,174 @@
+// Package fsutil provides helpers for working with directories on the local filesystem.
+package fsutil
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+)
+
+// DirSize returns the accumulated size of all regular files below the directory at path. Symbolic links are not
+// followed.
+func DirSize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "walk %q", path)
+	}
+
+	return size, nil
+}
+
+// IsDir reports whether path exists and is a directory.
+func IsDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+// FormatSize formats the number of bytes in a human-readable way, e.g. 1.5 MiB.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// ExpandPath expands environment variables in the path and replaces a leading ~ with the home directory of the user.
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// ErrSourceNotRemoved is returned by Move if src was copied to dst completely, but could not be removed afterwards. dst
+// holds a complete copy in that case, while parts of src might be gone already.
+var ErrSourceNotRemoved = errors.New("source was copied but not removed")
+
+// Move moves the file or directory at src to dst. The parent directory of dst gets created if needed. If src and dst
+// are located on different devices, src gets copied to dst and removed afterwards.
+func Move(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return errors.Newf("destination %q already exists", dst)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return errors.Wrapf(err, "create directory %q", filepath.Dir(dst))
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return errors.Wrapf(err, "rename %q to %q", src, dst)
+	}
+
+	// Different devices; fall back to copy and delete
+	if err = copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst) // Don't leave a partial copy behind
+
+		return errors.Wrapf(err, "copy %q to %q", src, dst)
+	}
+
+	if err = os.RemoveAll(src); err != nil {
+		return errors.Mark(errors.Wrapf(err, "remove %q after copying it to %q", src, dst), ErrSourceNotRemoved)
+	}
+
+	return nil
+}
+
+// copyTree recursively copies the file or directory at src to dst. File modes and symbolic links are preserved.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return errors.Newf("unsupported file type of %q", path)
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,170 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create directory %q: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write file %q: %v", path, err)
+		}
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.txt":       "12345",
+		"sub/b.txt":   "123",
+		"sub/c/d.txt": "",
+	})
+
+	got, err := DirSize(root)
+	if err != nil {
+		t.Fatalf("DirSize() returned an unexpected error: %v", err)
+	}
+	if got != 8 {
+		t.Fatalf("DirSize() = %d, want 8", got)
+	}
+
+	if _, err = DirSize(filepath.Join(root, "missing")); err == nil {
+		t.Fatal("DirSize() didn't fail for a missing directory")
+	}
+}
+
+func TestIsDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": ""})
+
+	if !IsDir(root) {
+		t.Fatalf("IsDir(%q) = false, want true", root)
+	}
+	if IsDir(filepath.Join(root, "a.txt")) {
+		t.Fatal("IsDir() = true for a file")
+	}
+	if IsDir(filepath.Join(root, "missing")) {
+		t.Fatal("IsDir() = true for a missing path")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KiB"},
+		{size: 1536, want: "1.5 KiB"},
+		{size: 5 * 1024 * 1024, want: "5.0 MiB"},
+		{size: 3 * 1024 * 1024 * 1024, want: "3.0 GiB"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatSize(tc.size); got != tc.want {
+				t.Fatalf("FormatSize(%d) = %q, want %q", tc.size, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PROJI_TEST_DIR", "code")
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: ""},
+		{path: "/tmp/app", want: "/tmp/app"},
+		{path: "~", want: home},
+		{path: "~/code/app", want: filepath.Join(home, "code", "app")},
+		{path: "~user/app", want: "~user/app"},
+		{path: "$HOME/$PROJI_TEST_DIR/app", want: filepath.Join(home, "code", "app")},
+		{path: "~/${PROJI_TEST_DIR}", want: filepath.Join(home, "code")},
+	}
+
+	for _, tc := range cases {
+		if got := ExpandPath(tc.path); got != tc.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := filepath.Join(root, "project")
+	writeTree(t, src, map[string]string{"README.md": "hello", "cmd/main.go": "package main"})
+
+	dst := filepath.Join(root, "archive", "project")
+	if err := Move(src, dst); err != nil {
+		t.Fatalf("Move() returned an unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("source %q still exists after move", src)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dst, "cmd", "main.go"))
+	if err != nil {
+		t.Fatalf("read moved file: %v", err)
+	}
+	if string(content) != "package main" {
+		t.Fatalf("moved file has content %q, want %q", content, "package main")
+	}
+
+	// Moving onto an existing path must fail
+	writeTree(t, src, map[string]string{"README.md": "hello"})
+	if err = Move(src, dst); err == nil {
+		t.Fatal("Move() overwrote an existing destination")
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	writeTree(t, src, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+
+	dst := filepath.Join(root, "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree() returned an unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+	if err != nil || string(content) != "b" {
+		t.Fatalf("copied file has content %q (err: %v), want %q", content, err, "b")
+	}
+
+	link, err := os.Readlink(filepath.Join(dst, "link"))
+	if err != nil || link != "a.txt" {
+		t.Fatalf("copied link points to %q (err: %v), want %q", link, err, "a.txt")
+	}
+}
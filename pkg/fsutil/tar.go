@@ -0,0 +1,151 @@
+package fsutil
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// PackDir writes the content of the directory at dir as gzip compressed tar archive to w. Paths inside the archive are
+// relative to dir. Directories, regular files and symbolic links are supported.
+func PackDir(w io.Writer, dir string) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !entry.IsDir() && !entry.Type().IsRegular() {
+			return errors.Newf("unsupported file type of %q", path)
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+
+		_, err = io.Copy(tarWriter, file)
+
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "pack %q", dir)
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "close tar writer")
+	}
+
+	return errors.Wrap(gzipWriter.Close(), "close gzip writer")
+}
+
+// UnpackDir extracts the gzip compressed tar archive from r into the directory at dir. The directory gets created if
+// needed. Entries that would end up outside of dir are rejected.
+func UnpackDir(r io.Reader, dir string) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "open gzip reader")
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "create directory %q", dir)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "read archive")
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if rel, err := filepath.Rel(dir, target); err != nil || rel == ".." ||
+			strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return errors.Newf("archive entry %q points outside of %q", header.Name, dir)
+		}
+
+		if err = unpackEntry(tarReader, header, target); err != nil {
+			return errors.Wrapf(err, "unpack %q", header.Name)
+		}
+	}
+}
+
+func unpackEntry(r io.Reader, header *tar.Header, target string) error {
+	mode := header.FileInfo().Mode().Perm()
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode)
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(file, r); err != nil { //nolint:gosec // Archives are created by proji itself
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+	default:
+		return errors.Newf("unsupported entry type %q", header.Typeflag)
+	}
+}
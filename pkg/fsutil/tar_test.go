@@ -0,0 +1,70 @@
+package fsutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackUnpackDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	writeTree(t, src, map[string]string{"README.md": "hello", "cmd/app/main.go": "package main"})
+	if err := os.MkdirAll(filepath.Join(src, "empty"), 0o755); err != nil {
+		t.Fatalf("create empty directory: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := PackDir(&buf, src); err != nil {
+		t.Fatalf("PackDir() returned an unexpected error: %v", err)
+	}
+
+	dst := filepath.Join(root, "dst")
+	if err := UnpackDir(&buf, dst); err != nil {
+		t.Fatalf("UnpackDir() returned an unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dst, "cmd", "app", "main.go"))
+	if err != nil || string(content) != "package main" {
+		t.Fatalf("unpacked file has content %q (err: %v), want %q", content, err, "package main")
+	}
+	if info, err := os.Stat(filepath.Join(dst, "empty")); err != nil || !info.IsDir() {
+		t.Fatalf("empty directory was not unpacked (err: %v)", err)
+	}
+}
+
+func TestUnpackDir_RejectsTraversal(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	content := []byte("evil")
+	header := &tar.Header{Name: "../evil.txt", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		t.Fatalf("write content: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("close tar writer: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+
+	root := t.TempDir()
+	if err := UnpackDir(&buf, filepath.Join(root, "dst")); err == nil {
+		t.Fatal("UnpackDir() accepted an entry outside of the target directory")
+	}
+	if _, err := os.Stat(filepath.Join(root, "evil.txt")); !os.IsNotExist(err) {
+		t.Fatal("UnpackDir() wrote a file outside of the target directory")
+	}
+}
@@ -0,0 +1,72 @@
+package fsutil
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Usage summarizes the disk usage of a directory.
+type Usage struct {
+	Size         int64     `json:"size"`  // Accumulated size of all regular files in bytes
+	Files        int       `json:"files"` // Number of regular files
+	Dirs         int       `json:"dirs"`  // Number of directories, not counting the root
+	LastModified time.Time `json:"last_modified"`
+}
+
+// DirUsage walks the directory at path and returns its disk usage. Paths relative to the directory that match exclude
+// are skipped; for directories, this includes everything below them. The modification time of the root itself is not
+// taken into account. Symbolic links are not followed.
+func DirUsage(path string, exclude *regexp.Regexp) (*Usage, error) {
+	usage := &Usage{}
+	hasExcludePattern := exclude != nil && exclude.String() != ""
+
+	err := filepath.WalkDir(path, func(currentPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if currentPath == path {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(path, currentPath)
+		if err != nil {
+			return err
+		}
+
+		if hasExcludePattern && exclude.MatchString(relPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(usage.LastModified) {
+			usage.LastModified = info.ModTime()
+		}
+
+		switch {
+		case entry.IsDir():
+			usage.Dirs++
+		case entry.Type().IsRegular():
+			usage.Files++
+			usage.Size += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "walk %q", path)
+	}
+
+	return usage, nil
+}
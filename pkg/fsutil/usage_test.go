@@ -0,0 +1,67 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDirUsage(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.txt":          "12345",
+		"sub/b.txt":      "123",
+		".git/HEAD":      "ref: refs/heads/main",
+		"sub/.env":       "SECRET=1",
+		"sub/deep/c.txt": "",
+	})
+
+	lastModified := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(root, "sub", "b.txt"), lastModified, lastModified); err != nil {
+		t.Fatalf("change times: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		exclude *regexp.Regexp
+		want    *Usage
+	}{
+		{
+			name:    "no exclude pattern",
+			exclude: nil,
+			want:    &Usage{Size: 36, Files: 5, Dirs: 3, LastModified: lastModified},
+		},
+		{
+			name:    "exclude dot files",
+			exclude: regexp.MustCompile(`(^|/)\.(git|env)$`),
+			want:    &Usage{Size: 8, Files: 3, Dirs: 2, LastModified: lastModified},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DirUsage(root, tc.exclude)
+			if err != nil {
+				t.Fatalf("DirUsage() returned an unexpected error: %v", err)
+			}
+
+			got.LastModified = got.LastModified.UTC()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("DirUsage() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := DirUsage(filepath.Join(root, "missing"), nil); err == nil {
+		t.Fatal("DirUsage() didn't fail for a missing directory")
+	}
+}
@@ -0,0 +1,162 @@
+// Package gitstatus inspects the state of git repositories by calling the local git binary.
+package gitstatus
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrGitNotFound is returned if no git binary is available.
+var ErrGitNotFound = errors.New("git binary not found")
+
+// Status is the state of a git repository.
+type Status struct {
+	IsRepo     bool       `json:"is_repo"`
+	Branch     string     `json:"branch,omitempty"`      // Empty if HEAD is detached
+	Upstream   string     `json:"upstream,omitempty"`    // Empty if the branch has no upstream
+	Changes    int        `json:"changes"`               // Number of changed and untracked files
+	Unpushed   int        `json:"unpushed"`              // Commits not on the upstream or, without one, on any remote
+	Behind     int        `json:"behind"`                // Commits on the upstream that are not on the branch
+	LastCommit *time.Time `json:"last_commit,omitempty"` // Nil if the repository has no commits
+
+	hasCommits bool
+}
+
+// Dirty reports whether the working tree has changed or untracked files.
+func (s *Status) Dirty() bool {
+	return s.Changes > 0
+}
+
+// git runs git with the given arguments inside of dir and returns its trimmed output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", ErrGitNotFound
+		}
+
+		return "", errors.Wrapf(err, "git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// parseStatus parses the output of 'git status --porcelain=v2 --branch'.
+func parseStatus(output string) *Status {
+	status := &Status{IsRepo: true}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "# ") {
+			status.Changes++
+			continue
+		}
+
+		fields := strings.Fields(line[2:])
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "branch.oid":
+			status.hasCommits = fields[1] != "(initial)"
+		case "branch.head":
+			if fields[1] != "(detached)" {
+				status.Branch = fields[1]
+			}
+		case "branch.upstream":
+			status.Upstream = fields[1]
+		case "branch.ab":
+			if len(fields) < 3 {
+				continue
+			}
+
+			status.Unpushed, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "-"))
+		}
+	}
+
+	return status
+}
+
+// isTopLevel reports whether dir is the top-level directory of the git repository that contains it.
+func isTopLevel(ctx context.Context, dir string) bool {
+	topLevel, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil || topLevel == "" {
+		return false
+	}
+
+	// Resolve symbolic links on both sides; git reports the physical path of the repository.
+	if topLevel, err = filepath.EvalSymlinks(topLevel); err != nil {
+		return false
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return false
+	}
+
+	return filepath.Clean(topLevel) == filepath.Clean(dir)
+}
+
+// Inspect returns the status of the git repository at dir. If dir is not the root of a git repository, e.g. because
+// it's not part of one or only a subdirectory of an enclosing repository, a status with IsRepo set to false is
+// returned.
+func Inspect(ctx context.Context, dir string) (*Status, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	if !isTopLevel(ctx, dir) {
+		return &Status{}, nil
+	}
+
+	output, err := git(ctx, dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+
+	status := parseStatus(output)
+	if !status.hasCommits {
+		return status, nil
+	}
+
+	// Without an upstream, every commit that's not on any remote counts as unpushed
+	if status.Upstream == "" {
+		output, err = git(ctx, dir, "rev-list", "--count", "HEAD", "--not", "--remotes")
+		if err != nil {
+			return nil, err
+		}
+
+		if status.Unpushed, err = strconv.Atoi(output); err != nil {
+			return nil, errors.Wrapf(err, "parse unpushed commit count %q", output)
+		}
+	}
+
+	output, err = git(ctx, dir, "log", "-1", "--format=%cI")
+	if err != nil {
+		return nil, err
+	}
+
+	lastCommit, err := time.Parse(time.RFC3339, output)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse commit date %q", output)
+	}
+	status.LastCommit = &lastCommit
+
+	return status, nil
+}
@@ -0,0 +1,126 @@
+package gitstatus
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseStatus(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		output string
+		want   *Status
+	}{
+		{
+			name:   "empty repository",
+			output: "# branch.oid (initial)\n# branch.head main\n",
+			want:   &Status{IsRepo: true, Branch: "main"},
+		},
+		{
+			name: "dirty branch with upstream",
+			output: "# branch.oid 2c1f0e7d\n# branch.head feature\n# branch.upstream origin/feature\n" +
+				"# branch.ab +2 -1\n1 .M N... 100644 100644 100644 aaaa bbbb main.go\n? notes.txt\n",
+			want: &Status{
+				IsRepo: true, Branch: "feature", Upstream: "origin/feature", Changes: 2, Unpushed: 2, Behind: 1,
+				hasCommits: true,
+			},
+		},
+		{
+			name:   "detached head",
+			output: "# branch.oid 2c1f0e7d\n# branch.head (detached)\n",
+			want:   &Status{IsRepo: true, hasCommits: true},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := parseStatus(tc.output)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(Status{})); diff != "" {
+				t.Fatalf("parseStatus() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Not a repository
+	got, err := Inspect(ctx, dir)
+	if err != nil {
+		t.Fatalf("Inspect() returned an unexpected error: %v", err)
+	}
+	if got.IsRepo {
+		t.Fatalf("Inspect() reported %q as repository", dir)
+	}
+
+	run := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=proji", "GIT_AUTHOR_EMAIL=proji@example.com",
+			"GIT_COMMITTER_NAME=proji", "GIT_COMMITTER_EMAIL=proji@example.com",
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	run("init", "--quiet", "--initial-branch=main")
+	if err = os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	run("add", "README.md")
+	run("commit", "--quiet", "-m", "Initial commit")
+	if err = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("todo"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	got, err = Inspect(ctx, dir)
+	if err != nil {
+		t.Fatalf("Inspect() returned an unexpected error: %v", err)
+	}
+
+	want := &Status{IsRepo: true, Branch: "main", Changes: 1, Unpushed: 1, hasCommits: true}
+	diff := cmp.Diff(want, got, cmp.AllowUnexported(Status{}), cmpopts.IgnoreFields(Status{}, "LastCommit"))
+	if diff != "" {
+		t.Fatalf("Inspect() mismatch (-want +got):\n%s", diff)
+	}
+	if got.LastCommit == nil || got.LastCommit.IsZero() {
+		t.Fatal("Inspect() returned no last commit date")
+	}
+
+	// A subdirectory of a repository is not a repository on its own
+	subdir := filepath.Join(dir, "sub")
+	if err = os.Mkdir(subdir, 0o755); err != nil {
+		t.Fatalf("create directory: %v", err)
+	}
+
+	got, err = Inspect(ctx, subdir)
+	if err != nil {
+		t.Fatalf("Inspect() returned an unexpected error: %v", err)
+	}
+	if got.IsRepo {
+		t.Fatalf("Inspect() reported subdirectory %q as repository", subdir)
+	}
+}
@@ -0,0 +1,88 @@
+package packages
+
+import (
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+// Compose layers the given packages in order into a single package. The merge rules are:
+//
+//   - Directory tree entries are merged by their path. If multiple layers define an entry with the same path, the entry
+//     of the later layer wins but keeps the position of the first occurrence. Defining the same path as a directory in
+//     one layer and as a file in another is a conflict and results in an error.
+//   - Plugins keep their stages. All pre-creation plugins run in layer order before the directory tree gets created
+//     and all post-creation plugins run in layer order afterwards.
+//   - Notes are concatenated in layer order, separated by a blank line.
+//
+// The label and name of the composed package are the joined labels and names of its layers; the destination is the one
+// of the first layer. A single layer is returned as is.
+func Compose(layers ...*domain.Package) (*domain.Package, error) {
+	switch len(layers) {
+	case 0:
+		return nil, errors.New("no packages to compose")
+	case 1:
+		return layers[0], nil
+	}
+
+	labels := make([]string, 0, len(layers))
+	names := make([]string, 0, len(layers))
+	var notes []string
+	composed := &domain.Package{
+		DirTree: &domain.DirTree{},
+		Plugins: &domain.PluginScheduler{},
+	}
+
+	// Index of entries by their normalized path
+	entries := make(map[string]int)
+
+	for _, layer := range layers {
+		if layer == nil {
+			return nil, errors.New("package is nil")
+		}
+
+		labels = append(labels, layer.Label)
+		names = append(names, layer.Name)
+		if layer.Notes != nil && strings.TrimSpace(*layer.Notes) != "" {
+			notes = append(notes, strings.TrimSpace(*layer.Notes))
+		}
+
+		if layer.DirTree != nil {
+			for _, entry := range layer.DirTree.Entries {
+				key := path.Clean(strings.ReplaceAll(entry.Path, "\\", "/"))
+
+				idx, exists := entries[key]
+				if !exists {
+					entries[key] = len(composed.DirTree.Entries)
+					composed.DirTree.Entries = append(composed.DirTree.Entries, entry)
+
+					continue
+				}
+
+				if composed.DirTree.Entries[idx].IsDir != entry.IsDir {
+					return nil, errors.Newf("package %q defines %q as a file and as a directory", layer.Label, key)
+				}
+
+				composed.DirTree.Entries[idx] = entry
+			}
+		}
+
+		if layer.Plugins != nil {
+			composed.Plugins.Pre = append(composed.Plugins.Pre, layer.Plugins.Pre...)
+			composed.Plugins.Post = append(composed.Plugins.Post, layer.Plugins.Post...)
+		}
+	}
+
+	composed.Label = strings.Join(labels, "+")
+	composed.Name = strings.Join(names, " + ")
+	composed.Destination = layers[0].Destination
+	if len(notes) > 0 {
+		joined := strings.Join(notes, "\n\n")
+		composed.Notes = &joined
+	}
+
+	return composed, nil
+}
@@ -0,0 +1,126 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	destination := "~/code/go/%{{project-name}}%"
+	baseNotes, dockerNotes, composedNotes := "Run make dev", "Run docker build .", "Run make dev\n\nRun docker build ."
+	base := &domain.Package{
+		Label:       "gs",
+		Name:        "Go Service",
+		Destination: &destination,
+		Notes:       &baseNotes,
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "cmd", IsDir: true},
+			{Path: "README.md", Template: &domain.Template{Path: "go/README.md"}},
+			{Path: "Makefile"},
+		}},
+		Plugins: &domain.PluginScheduler{
+			Pre:  []*domain.Plugin{{Path: "go-init.lua"}},
+			Post: []*domain.Plugin{{Path: "git-init.lua"}},
+		},
+	}
+	docker := &domain.Package{
+		Label: "dkr",
+		Name:  "Docker",
+		Notes: &dockerNotes,
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "Dockerfile", Template: &domain.Template{Path: "docker/Dockerfile"}},
+			{Path: "./README.md", Template: &domain.Template{Path: "docker/README.md"}},
+		}},
+		Plugins: &domain.PluginScheduler{
+			Post: []*domain.Plugin{{Path: "docker-build.lua"}},
+		},
+	}
+	actions := &domain.Package{
+		Label: "gha",
+		Name:  "GitHub Actions",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: ".github/workflows", IsDir: true},
+			{Path: "cmd/", IsDir: true},
+		}},
+		Plugins: &domain.PluginScheduler{
+			Pre: []*domain.Plugin{{Path: "gh-login.lua"}},
+		},
+	}
+	conflicting := &domain.Package{
+		Label: "cfl",
+		Name:  "Conflicting",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "Makefile", IsDir: true},
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		layers  []*domain.Package
+		want    *domain.Package
+		wantErr bool
+	}{
+		{
+			name:    "no layers",
+			layers:  nil,
+			wantErr: true,
+		},
+		{
+			name:   "single layer",
+			layers: []*domain.Package{base},
+			want:   base,
+		},
+		{
+			name:   "multiple layers",
+			layers: []*domain.Package{base, docker, actions},
+			want: &domain.Package{
+				Label:       "gs+dkr+gha",
+				Name:        "Go Service + Docker + GitHub Actions",
+				Destination: &destination,
+				Notes:       &composedNotes,
+				DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+					{Path: "cmd/", IsDir: true},
+					{Path: "./README.md", Template: &domain.Template{Path: "docker/README.md"}},
+					{Path: "Makefile"},
+					{Path: "Dockerfile", Template: &domain.Template{Path: "docker/Dockerfile"}},
+					{Path: ".github/workflows", IsDir: true},
+				}},
+				Plugins: &domain.PluginScheduler{
+					Pre:  []*domain.Plugin{{Path: "go-init.lua"}, {Path: "gh-login.lua"}},
+					Post: []*domain.Plugin{{Path: "git-init.lua"}, {Path: "docker-build.lua"}},
+				},
+			},
+		},
+		{
+			name:    "file and directory conflict",
+			layers:  []*domain.Package{base, conflicting},
+			wantErr: true,
+		},
+		{
+			name:    "nil layer",
+			layers:  []*domain.Package{base, nil},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Compose(tc.layers...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Compose() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Compose() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -86,6 +86,13 @@ func (m *localManager) Store(ctx context.Context, _package *domain.PackageAdd) e
 
 // Update updates a package on the local storage.
 func (m *localManager) Update(ctx context.Context, _package *domain.PackageUpdate) error {
+	if _package != nil && _package.DirTree != nil {
+		dependencies := &domain.PackageAdd{DirTree: _package.DirTree, Plugins: _package.Plugins}
+		if err := m.downloadDependencies(ctx, dependencies); err != nil {
+			return errors.Wrap(err, "download dependencies")
+		}
+	}
+
 	return m.packageService.Update(ctx, _package)
 }
 
@@ -94,6 +101,31 @@ func (m *localManager) Remove(ctx context.Context, id string) error {
 	return m.packageService.Remove(ctx, id)
 }
 
+// History fetches all revisions of a package from the local storage, oldest first.
+func (m *localManager) History(ctx context.Context, label string) ([]domain.PackageRevision, error) {
+	return m.packageService.History(ctx, label)
+}
+
+// GetRevision fetches a single revision of a package from the local storage.
+func (m *localManager) GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error) {
+	return m.packageService.GetRevision(ctx, label, revision)
+}
+
+// Rollback restores a revision of a package on the local storage. The restored definition becomes a new revision.
+func (m *localManager) Rollback(ctx context.Context, label string, revision int) error {
+	stored, err := m.GetRevision(ctx, label, revision)
+	if err != nil {
+		return errors.Wrapf(err, "get revision %d of package %q", revision, label)
+	}
+
+	update, err := rollbackUpdate(&stored)
+	if err != nil {
+		return err
+	}
+
+	return m.Update(ctx, update)
+}
+
 // String returns the name of the local package manager - "local".
 func (m *localManager) String() string {
 	return "local"
@@ -2,6 +2,9 @@ package packages
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
 
 	"github.com/nikoksr/proji/pkg/api/v1/domain"
 )
@@ -13,5 +16,21 @@ type Manager interface {
 	Store(ctx context.Context, _package *domain.PackageAdd) error
 	Update(ctx context.Context, _package *domain.PackageUpdate) error
 	Remove(ctx context.Context, id string) error
+	History(ctx context.Context, label string) ([]domain.PackageRevision, error)
+	GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error)
+	Rollback(ctx context.Context, label string, revision int) error
 	String() string
 }
+
+// rollbackUpdate returns the update that restores the given revision of a package. Restoring a revision doesn't
+// rewrite the history; the restored definition is recorded as a new revision instead.
+func rollbackUpdate(revision *domain.PackageRevision) (*domain.PackageUpdate, error) {
+	if revision == nil || revision.Package == nil {
+		return nil, errors.New("revision has no package")
+	}
+
+	update := revision.Package.AsUpdatable()
+	update.Source = fmt.Sprintf("rollback to revision %d", revision.Revision)
+
+	return update, nil
+}
@@ -0,0 +1,98 @@
+package packages
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+// templateTag marks templated entry paths. Their final path depends on the template values, so they can't be compared
+// against an existing directory.
+const templateTag = "%{{"
+
+// TreeMatch describes how well the directory tree of a package matches an existing directory.
+type TreeMatch struct {
+	Package *domain.Package
+	Matched int     // Number of entries that exist in the directory
+	Total   int     // Number of entries that were compared
+	Score   float64 // Matched divided by Total
+}
+
+// matchTree compares the directory tree of the package against the directory at root in fs.
+func matchTree(fs afero.Fs, root string, _package *domain.Package) (*TreeMatch, error) {
+	match := &TreeMatch{Package: _package}
+	if _package.DirTree == nil {
+		return match, nil
+	}
+
+	for _, entry := range _package.DirTree.Entries {
+		if strings.Contains(entry.Path, templateTag) {
+			continue
+		}
+
+		entryPath := path.Clean(strings.ReplaceAll(entry.Path, "\\", "/"))
+		if entryPath == "." {
+			continue
+		}
+
+		match.Total++
+
+		info, err := fs.Stat(filepath.Join(root, filepath.FromSlash(entryPath)))
+		if err != nil {
+			// A file where the entry expects a directory above it means that the entry doesn't exist either
+			if errors.Is(err, afero.ErrFileNotFound) || errors.Is(err, syscall.ENOTDIR) {
+				continue
+			}
+
+			return nil, errors.Wrapf(err, "stat %q", entryPath)
+		}
+
+		if info.IsDir() == entry.IsDir {
+			match.Matched++
+		}
+	}
+
+	if match.Total > 0 {
+		match.Score = float64(match.Matched) / float64(match.Total)
+	}
+
+	return match, nil
+}
+
+// MatchTree compares the directory trees of the given packages against the directory at root in fs. It returns the
+// packages that match at least one entry, best match first. Packages with equal scores are ordered by the number of
+// matched entries, so that the more specific package comes first.
+func MatchTree(fs afero.Fs, root string, _packages []*domain.Package) ([]*TreeMatch, error) {
+	matches := make([]*TreeMatch, 0, len(_packages))
+	for _, _package := range _packages {
+		match, err := matchTree(fs, root, _package)
+		if err != nil {
+			return nil, errors.Wrapf(err, "match package %q", _package.Label)
+		}
+		if match.Matched == 0 {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].Matched != matches[j].Matched {
+			return matches[i].Matched > matches[j].Matched
+		}
+
+		return matches[i].Package.Label < matches[j].Package.Label
+	})
+
+	return matches, nil
+}
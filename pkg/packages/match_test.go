@@ -0,0 +1,115 @@
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+func TestMatchTree(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, dir := range []string{"/repo/cmd", "/repo/internal", "/repo/Dockerfile.d"} {
+		if err := fs.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create directory %q: %v", dir, err)
+		}
+	}
+	for _, file := range []string{"/repo/go.mod", "/repo/README.md", "/repo/Makefile"} {
+		if err := afero.WriteFile(fs, file, nil, 0o644); err != nil {
+			t.Fatalf("create file %q: %v", file, err)
+		}
+	}
+
+	_packages := []*domain.Package{
+		{
+			Label: "go",
+			DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+				{Path: "cmd", IsDir: true},
+				{Path: "internal", IsDir: true},
+				{Path: "go.mod"},
+				{Path: "cmd/%{{project-name}}%/main.go"}, // Templated paths are ignored
+			}},
+		},
+		{
+			Label: "go-lib",
+			DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+				{Path: "./go.mod"},
+				{Path: "README.md"},
+				{Path: "LICENSE"},
+			}},
+		},
+		{
+			Label: "dkr",
+			DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+				{Path: "Dockerfile"},
+				{Path: "Dockerfile.d"}, // Exists, but as a directory
+			}},
+		},
+		{
+			Label: "py",
+			DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+				{Path: "setup.py"},
+			}},
+		},
+		{Label: "empty"},
+	}
+
+	matches, err := MatchTree(fs, "/repo", _packages)
+	if err != nil {
+		t.Fatalf("MatchTree() returned an unexpected error: %v", err)
+	}
+
+	type result struct {
+		Label          string
+		Matched, Total int
+	}
+
+	got := make([]result, 0, len(matches))
+	for _, match := range matches {
+		got = append(got, result{Label: match.Package.Label, Matched: match.Matched, Total: match.Total})
+	}
+
+	want := []result{
+		{Label: "go", Matched: 3, Total: 3},
+		{Label: "go-lib", Matched: 2, Total: 3},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("MatchTree() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMatchTree_FileInPath(t *testing.T) {
+	t.Parallel()
+
+	// On a real filesystem, a file in place of a parent directory makes stat fail with ENOTDIR
+	root := t.TempDir()
+	fs := afero.NewOsFs()
+	if err := afero.WriteFile(fs, filepath.Join(root, "cmd"), nil, 0o644); err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	if err := afero.WriteFile(fs, filepath.Join(root, "go.mod"), nil, 0o644); err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+
+	_packages := []*domain.Package{{
+		Label: "go",
+		DirTree: &domain.DirTree{Entries: []*domain.DirEntry{
+			{Path: "cmd/app/main.go"},
+			{Path: "go.mod"},
+		}},
+	}}
+
+	matches, err := MatchTree(fs, root, _packages)
+	if err != nil {
+		t.Fatalf("MatchTree() returned an unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Matched != 1 || matches[0].Total != 2 {
+		t.Fatalf("MatchTree() = %+v, want one match with 1 of 2 entries", matches)
+	}
+}
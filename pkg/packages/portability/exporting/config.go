@@ -28,6 +28,30 @@ func encodeTOML(data *bytes.Buffer, pkg *domain.PackageConfig) error {
 	return enc.Encode(pkg)
 }
 
+// Encode returns the package config in the given file type, exactly as it would be written to a config file.
+func Encode(pkg *domain.PackageConfig, fileType string) ([]byte, error) {
+	if pkg == nil {
+		return nil, errors.New("package is nil")
+	}
+
+	var err error
+	data := new(bytes.Buffer)
+
+	switch fileType {
+	case portability.FileTypeTOML:
+		err = encodeTOML(data, pkg)
+	case portability.FileTypeJSON:
+		err = encodeJSON(data, pkg)
+	default:
+		err = portability.ErrUnsupportedConfigFileType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
 func write(_ context.Context, file *os.File, data *bytes.Buffer) error {
 	// If no data is given, we return nil. This is an okay behavior, because the caller might want to write an empty
 	// file.
@@ -57,26 +81,14 @@ func write(_ context.Context, file *os.File, data *bytes.Buffer) error {
 }
 
 func toConfig(ctx context.Context, pkg *domain.PackageConfig, dir, fileType string) (string, error) {
-	if pkg == nil {
-		return "", errors.New("package is nil")
-	}
-
-	var err error
-	data := new(bytes.Buffer)
-	fileName := "proji-" + pkg.Name + ".*." + fileType
-
-	switch fileType {
-	case portability.FileTypeTOML:
-		err = encodeTOML(data, pkg)
-	case portability.FileTypeJSON:
-		err = encodeJSON(data, pkg)
-	default:
-		err = portability.ErrUnsupportedConfigFileType
-	}
+	encoded, err := Encode(pkg, fileType)
 	if err != nil {
 		return "", err
 	}
 
+	data := bytes.NewBuffer(encoded)
+	fileName := "proji-" + pkg.Name + ".*." + fileType
+
 	// Open file; if dir is empty, a temporary file will be created.
 	file, err := os.CreateTemp(dir, fileName)
 	if err != nil {
@@ -5,6 +5,9 @@ import (
 	"context"
 	"os"
 	"testing"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/packages/portability"
 )
 
 func Test_write(t *testing.T) {
@@ -75,3 +78,57 @@ func Test_write(t *testing.T) {
 		})
 	}
 }
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	pkg := &domain.PackageConfig{Label: "tst", Name: "Test"}
+
+	cases := []struct {
+		name     string
+		pkg      *domain.PackageConfig
+		fileType string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "toml",
+			pkg:      pkg,
+			fileType: portability.FileTypeTOML,
+			want:     "label = 'tst'\nname = 'Test'\n",
+		},
+		{
+			name:     "json",
+			pkg:      pkg,
+			fileType: portability.FileTypeJSON,
+			want:     "{\n  \"label\": \"tst\",\n  \"name\": \"Test\"\n}\n",
+		},
+		{
+			name:     "unsupported file type",
+			pkg:      pkg,
+			fileType: "yaml",
+			wantErr:  true,
+		},
+		{
+			name:     "nil package",
+			fileType: portability.FileTypeTOML,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Encode(tc.pkg, tc.fileType)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Encode() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if string(got) != tc.want {
+				t.Fatalf("Encode() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -60,6 +60,31 @@ func (m *remoteManager) Remove(ctx context.Context, label string) error {
 	return m.client.Remove(ctx, label)
 }
 
+// History fetches all revisions of a package from the remote server, oldest first.
+func (m *remoteManager) History(ctx context.Context, label string) ([]domain.PackageRevision, error) {
+	return m.client.History(ctx, label)
+}
+
+// GetRevision fetches a single revision of a package from the remote server.
+func (m *remoteManager) GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error) {
+	return m.client.GetRevision(ctx, label, revision)
+}
+
+// Rollback restores a revision of a package on the remote server. The restored definition becomes a new revision.
+func (m *remoteManager) Rollback(ctx context.Context, label string, revision int) error {
+	stored, err := m.GetRevision(ctx, label, revision)
+	if err != nil {
+		return errors.Wrapf(err, "get revision %d of package %q", revision, label)
+	}
+
+	update, err := rollbackUpdate(&stored)
+	if err != nil {
+		return err
+	}
+
+	return m.Update(ctx, update)
+}
+
 // String returns the name of the remote package manager - "remote".
 func (m *remoteManager) String() string {
 	return "remote"
@@ -9,20 +9,27 @@ import (
 )
 
 // TODO: This needs Windows support - sigh.
-func run(ctx context.Context, path string) error {
+func run(ctx context.Context, path, dir string) error {
 	logger := simplog.FromContext(ctx)
 
 	cmd := exec.Command("lua", path)
+	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	logger.Debugf("executing lua script %s", path)
+	logger.Debugf("executing lua script %s in %q", path, dir)
 
 	return cmd.Run()
 }
 
 // Run runs the lua script at path. It is equivalent to: `lua <path>`.
 func Run(ctx context.Context, path string) error {
-	return run(ctx, path)
+	return run(ctx, path, "")
+}
+
+// RunIn is similar to Run but runs the lua script with dir as its working directory. The working directory of the
+// calling process is left untouched.
+func RunIn(ctx context.Context, path, dir string) error {
+	return run(ctx, path, dir)
 }
@@ -0,0 +1,316 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/nikoksr/simplog"
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+type (
+	// ConflictPolicy defines how to handle files that already exist when scaffolding into an existing directory.
+	ConflictPolicy string
+
+	// Action describes what happened to a path during a build.
+	Action string
+
+	// ConflictFn is called for files that already exist if the policy is ConflictPrompt. It is expected to return the
+	// policy that should be applied to the file; returning ConflictPrompt again is treated as ConflictFail.
+	ConflictFn func(path string) (ConflictPolicy, error)
+
+	// Change is a single change that was made to the filesystem during a build.
+	Change struct {
+		Action Action
+		Path   string
+		Backup string // Path of the backup file; only set for backed up files
+	}
+
+	// Report collects all changes that were made to the filesystem during a build.
+	Report struct {
+		Changes []*Change
+	}
+
+	// Builder creates the directory tree of a package inside a project directory. All filesystem operations are
+	// performed on FS, which is expected to be rooted at the project directory; builds therefore never depend on the
+	// working directory of the process and may safely run concurrently.
+	Builder struct {
+		// FS is the filesystem of the project; its root is the project directory.
+		FS afero.Fs
+
+		// Templates is the filesystem that templates are read from. Relative template paths are resolved against
+		// TemplatesDir.
+		Templates    afero.Fs
+		TemplatesDir string
+
+		// Engine renders templated paths and template files.
+		Engine *templates.TemplateEngine
+
+		// Conflicts is the policy that is used for files that already exist. PromptConflict is called to resolve a
+		// conflict if the policy is ConflictPrompt.
+		Conflicts      ConflictPolicy
+		PromptConflict ConflictFn
+
+		// Report collects the changes that were made by the builder.
+		Report *Report
+
+		// Files records the directories and files that were generated by the builder, together with the content that
+		// was written. Skipped files are not recorded.
+		Files []*domain.ManifestFile
+	}
+)
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"      // Keep the existing file
+	ConflictOverwrite ConflictPolicy = "overwrite" // Replace the existing file
+	ConflictBackup    ConflictPolicy = "backup"    // Move the existing file aside and create a new one
+	ConflictPrompt    ConflictPolicy = "prompt"    // Ask the user for each conflict
+	ConflictFail      ConflictPolicy = "fail"      // Abort the build
+)
+
+const (
+	ActionCreated     Action = "created"
+	ActionOverwritten Action = "overwritten"
+	ActionBackedUp    Action = "backed up"
+	ActionSkipped     Action = "skipped"
+)
+
+// ParseConflictPolicy parses the given string into a ConflictPolicy. It returns an error if the policy is unknown. An
+// empty string is parsed as ConflictFail.
+func ParseConflictPolicy(policy string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(strings.ToLower(strings.TrimSpace(policy))); p {
+	case ConflictSkip, ConflictOverwrite, ConflictBackup, ConflictPrompt, ConflictFail:
+		return p, nil
+	case "":
+		return ConflictFail, nil
+	default:
+		return "", errors.Newf("unknown conflict policy %q; expected skip, overwrite, backup, prompt or fail", policy)
+	}
+}
+
+// New returns a builder that operates on the given project filesystem. Templates are read from the OS filesystem.
+func New(fs afero.Fs, templatesDir string, engine *templates.TemplateEngine) *Builder {
+	if engine == nil {
+		engine = templates.NewEngine("", "")
+	}
+
+	return &Builder{
+		FS:           fs,
+		Templates:    afero.NewOsFs(),
+		TemplatesDir: templatesDir,
+		Engine:       engine,
+		Conflicts:    ConflictFail,
+		Report:       &Report{},
+	}
+}
+
+// NewOS returns a builder that operates on the OS filesystem, rooted at the project directory root.
+func NewOS(root, templatesDir string, engine *templates.TemplateEngine) *Builder {
+	return New(afero.NewBasePathFs(afero.NewOsFs(), root), templatesDir, engine)
+}
+
+// add adds a change to the report.
+func (r *Report) add(action Action, path, backup string) {
+	if r == nil {
+		return
+	}
+
+	r.Changes = append(r.Changes, &Change{Action: action, Path: path, Backup: backup})
+}
+
+// resolveConflict returns the action that should be applied to the already existing file at path. If the policy says
+// so, PromptConflict gets called for a decision. An error is returned if the build should be aborted.
+func (b *Builder) resolveConflict(path string) (Action, error) {
+	policy := b.Conflicts
+	if policy == ConflictPrompt && b.PromptConflict != nil {
+		var err error
+		if policy, err = b.PromptConflict(path); err != nil {
+			return "", err
+		}
+	}
+
+	switch policy {
+	case ConflictSkip:
+		return ActionSkipped, nil
+	case ConflictOverwrite:
+		return ActionOverwritten, nil
+	case ConflictBackup:
+		return ActionBackedUp, nil
+	default:
+		return "", errors.Newf("file %q already exists", path)
+	}
+}
+
+// backupFile moves the file at path aside by renaming it. The backup file gets a timestamped suffix; if a backup with
+// that name exists already, e.g. from a backup in the same second, a counter is added to the name. Previous backups
+// are thus never overwritten. It returns the path of the backup file.
+func (b *Builder) backupFile(path string) (string, error) {
+	prefix := path + "." + time.Now().Format("20060102150405")
+	backupPath := prefix + ".bak"
+
+	for idx := 1; ; idx++ {
+		exists, err := afero.Exists(b.FS, backupPath)
+		if err != nil {
+			return "", errors.Wrapf(err, "check if backup file %q exists", backupPath)
+		}
+		if !exists {
+			break
+		}
+
+		backupPath = prefix + "." + strconv.Itoa(idx) + ".bak"
+	}
+
+	if err := b.FS.Rename(path, backupPath); err != nil {
+		return "", err
+	}
+
+	return backupPath, nil
+}
+
+// readTemplate reads the template file at path. Relative paths are resolved against the templates directory.
+func (b *Builder) readTemplate(path string) ([]byte, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(b.TemplatesDir, path)
+	}
+
+	templates := b.Templates
+	if templates == nil {
+		templates = afero.NewOsFs()
+	}
+
+	return afero.ReadFile(templates, path)
+}
+
+// CreateEntries creates all given entries in order.
+func (b *Builder) CreateEntries(ctx context.Context, entries []*domain.DirEntry) error {
+	for _, entry := range entries {
+		if err := b.CreateEntry(ctx, entry); err != nil {
+			return errors.Wrapf(err, "create directory tree entry %q", entry.Path)
+		}
+	}
+
+	return nil
+}
+
+// CreateEntry creates the given entry inside the project filesystem. Files that already exist are handled according to
+// the builder's conflict policy.
+func (b *Builder) CreateEntry(ctx context.Context, entry *domain.DirEntry) error {
+	logger := simplog.FromContext(ctx)
+
+	// Check if template path is a template string
+	entryPath := entry.Path
+	if parsedPath, err := b.Engine.ParseToString(ctx, entryPath); err != nil {
+		logger.Debugf("template path %q is not a template string", entryPath)
+	} else {
+		entryPath = parsedPath
+	}
+	entryPath = filepath.Clean(entryPath)
+
+	// If we have a file, get its directory and create it. This allows for implicit directory creation and may
+	// simplify the directory tree structure in a packages config vastly.
+	dirPath := entryPath
+	filePath := ""
+	if !entry.IsDir {
+		dirPath = filepath.Dir(entryPath)
+		filePath = entryPath
+	}
+
+	if entry.IsDir && dirPath != "." {
+		b.Files = append(b.Files, domain.NewManifestDir(dirPath))
+	}
+
+	if dirPath != "." {
+		exists, err := afero.DirExists(b.FS, dirPath)
+		if err != nil {
+			return errors.Wrapf(err, "check if directory %q exists", dirPath)
+		}
+		if !exists {
+			logger.Debugf("creating directory %q", dirPath)
+			if err = b.FS.MkdirAll(dirPath, 0o755); err != nil {
+				return errors.Wrapf(err, "create directory %q", dirPath)
+			}
+
+			b.Report.add(ActionCreated, dirPath+string(filepath.Separator), "")
+		}
+	}
+
+	// Skip if we don't have a file path
+	if filePath == "" {
+		return nil
+	}
+
+	// Resolve conflicts with already existing files before touching them
+	action := ActionCreated
+	exists, err := afero.Exists(b.FS, filePath)
+	if err != nil {
+		return errors.Wrapf(err, "check if file %q exists", filePath)
+	}
+	if exists {
+		if action, err = b.resolveConflict(filePath); err != nil {
+			return err
+		}
+
+		if action == ActionSkipped {
+			logger.Debugf("file %q already exists; skipping", filePath)
+			b.Report.add(ActionSkipped, filePath, "")
+			return nil
+		}
+	}
+
+	// Render the template before touching the file; a failing template must not leave an empty or truncated file
+	// behind.
+	var content []byte
+	if entry.Template != nil {
+		if entry.Template.Path == "" {
+			logger.Warnf("template %q has no path; skipping", entry.Template.ID)
+		} else {
+			logger.Debugf("generating file %q from template %q", entryPath, entry.Template.ID)
+			data, err := b.readTemplate(entry.Template.Path)
+			if err != nil {
+				return errors.Wrapf(err, "load template file %q", entry.Template.Path)
+			}
+
+			var buf bytes.Buffer
+			if err = b.Engine.Parse(ctx, &buf, data); err != nil {
+				return errors.Wrapf(err, "parse template from file %q", entry.Template.Path)
+			}
+			content = buf.Bytes()
+		}
+	}
+
+	if action == ActionBackedUp {
+		backupPath, err := b.backupFile(filePath)
+		if err != nil {
+			return errors.Wrapf(err, "back up file %q", filePath)
+		}
+
+		logger.Debugf("backed up file %q to %q", filePath, backupPath)
+		b.Report.add(ActionBackedUp, filePath, backupPath)
+	}
+
+	logger.Debugf("creating file %q", filePath)
+	if err = afero.WriteFile(b.FS, filePath, content, 0o644); err != nil {
+		return errors.Wrapf(err, "create file %q", filePath)
+	}
+
+	if action != ActionBackedUp {
+		b.Report.add(action, filePath, "")
+	}
+
+	// Only rendered templates keep their content; upgrades need it as base for merges.
+	file := domain.NewManifestFile(filePath, content)
+	if entry.Template != nil {
+		file.Content = content
+	}
+	b.Files = append(b.Files, file)
+
+	return nil
+}
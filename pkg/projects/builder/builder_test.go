@@ -0,0 +1,265 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/templates"
+)
+
+func TestParseConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		policy  string
+		want    ConflictPolicy
+		wantErr bool
+	}{
+		{name: "empty defaults to fail", policy: "", want: ConflictFail},
+		{name: "skip", policy: "skip", want: ConflictSkip},
+		{name: "case and whitespace", policy: " Backup ", want: ConflictBackup},
+		{name: "unknown", policy: "merge", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseConflictPolicy(tc.policy)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseConflictPolicy() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseConflictPolicy() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func newTestBuilder(t *testing.T, existing map[string]string) *Builder {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, content := range existing {
+		if err := afero.WriteFile(fs, path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write file %q: %v", path, err)
+		}
+	}
+
+	tmplFS := afero.NewMemMapFs()
+	if err := afero.WriteFile(tmplFS, "/templates/readme.md", []byte("# %{{Project Name}}%"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	engine := templates.NewEngine("", "")
+	engine.SetValues(map[string]string{"projectname": "proji", "dir": "docs"})
+
+	build := New(fs, "/templates", engine)
+	build.Templates = tmplFS
+
+	return build
+}
+
+func readFile(t *testing.T, fs afero.Fs, path string) string {
+	t.Helper()
+
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		t.Fatalf("failed to read file %q: %v", path, err)
+	}
+
+	return string(data)
+}
+
+func TestBuilder_CreateEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := []*domain.DirEntry{
+		{Path: "cmd", IsDir: true},
+		{Path: "%{{dir}}%/README.md", Template: &domain.Template{Path: "readme.md"}},
+		{Path: "Makefile"},
+	}
+
+	build := newTestBuilder(t, nil)
+	if err := build.CreateEntries(context.Background(), entries); err != nil {
+		t.Fatalf("CreateEntries() error = %v", err)
+	}
+
+	if exists, _ := afero.DirExists(build.FS, "cmd"); !exists {
+		t.Fatalf("expected directory %q to exist", "cmd")
+	}
+	if got := readFile(t, build.FS, "docs/README.md"); got != "# proji" {
+		t.Fatalf("expected rendered template %q, got %q", "# proji", got)
+	}
+	if got := readFile(t, build.FS, "Makefile"); got != "" {
+		t.Fatalf("expected empty file, got %q", got)
+	}
+
+	want := []*Change{
+		{Action: ActionCreated, Path: "cmd/"},
+		{Action: ActionCreated, Path: "docs/"},
+		{Action: ActionCreated, Path: "docs/README.md"},
+		{Action: ActionCreated, Path: "Makefile"},
+	}
+	if diff := cmp.Diff(want, build.Report.Changes); diff != "" {
+		t.Fatalf("report mismatch (-want +got):\n%s", diff)
+	}
+
+	readme := domain.NewManifestFile("docs/README.md", []byte("# proji"))
+	readme.Content = []byte("# proji")
+
+	wantFiles := []*domain.ManifestFile{
+		domain.NewManifestDir("cmd"),
+		readme,
+		domain.NewManifestFile("Makefile", nil),
+	}
+	if diff := cmp.Diff(wantFiles, build.Files); diff != "" {
+		t.Fatalf("files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_CreateEntry_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	entry := &domain.DirEntry{Path: "README.md", Template: &domain.Template{Path: "readme.md"}}
+
+	cases := []struct {
+		name       string
+		policy     ConflictPolicy
+		prompt     ConflictFn
+		wantAction Action
+		wantData   string
+		wantErr    bool
+	}{
+		{name: "fail", policy: ConflictFail, wantData: "old", wantErr: true},
+		{name: "skip", policy: ConflictSkip, wantAction: ActionSkipped, wantData: "old"},
+		{name: "overwrite", policy: ConflictOverwrite, wantAction: ActionOverwritten, wantData: "# proji"},
+		{name: "backup", policy: ConflictBackup, wantAction: ActionBackedUp, wantData: "# proji"},
+		{
+			name:       "prompt",
+			policy:     ConflictPrompt,
+			prompt:     func(string) (ConflictPolicy, error) { return ConflictOverwrite, nil },
+			wantAction: ActionOverwritten,
+			wantData:   "# proji",
+		},
+		{
+			name:       "prompt skip",
+			policy:     ConflictPrompt,
+			prompt:     func(string) (ConflictPolicy, error) { return ConflictSkip, nil },
+			wantAction: ActionSkipped,
+			wantData:   "old",
+		},
+		{
+			name:       "prompt backup",
+			policy:     ConflictPrompt,
+			prompt:     func(string) (ConflictPolicy, error) { return ConflictBackup, nil },
+			wantAction: ActionBackedUp,
+			wantData:   "# proji",
+		},
+		{
+			name:     "prompt fail",
+			policy:   ConflictPrompt,
+			prompt:   func(string) (ConflictPolicy, error) { return ConflictFail, nil },
+			wantData: "old",
+			wantErr:  true,
+		},
+		{
+			name:     "prompt error",
+			policy:   ConflictPrompt,
+			prompt:   func(string) (ConflictPolicy, error) { return "", errors.New("no input") },
+			wantData: "old",
+			wantErr:  true,
+		},
+		{name: "prompt without prompt function", policy: ConflictPrompt, wantData: "old", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			build := newTestBuilder(t, map[string]string{"README.md": "old"})
+			build.Conflicts = tc.policy
+			build.PromptConflict = tc.prompt
+
+			err := build.CreateEntry(context.Background(), entry)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CreateEntry() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := readFile(t, build.FS, "README.md"); got != tc.wantData {
+				t.Fatalf("expected file content %q, got %q", tc.wantData, got)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if len(build.Report.Changes) != 1 {
+				t.Fatalf("expected exactly one change, got %d", len(build.Report.Changes))
+			}
+			change := build.Report.Changes[0]
+			if change.Action != tc.wantAction {
+				t.Fatalf("expected action %q, got %q", tc.wantAction, change.Action)
+			}
+
+			if tc.wantAction == ActionBackedUp {
+				if !strings.HasPrefix(change.Backup, "README.md.") {
+					t.Fatalf("unexpected backup path %q", change.Backup)
+				}
+				if got := readFile(t, build.FS, change.Backup); got != "old" {
+					t.Fatalf("expected backup content %q, got %q", "old", got)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_CreateEntry_RepeatedBackups(t *testing.T) {
+	t.Parallel()
+
+	entry := &domain.DirEntry{Path: "README.md", Template: &domain.Template{Path: "readme.md"}}
+
+	build := newTestBuilder(t, map[string]string{"README.md": "old"})
+	build.Conflicts = ConflictBackup
+
+	// Backups that are made within the same second must not overwrite each other
+	for idx := 0; idx < 3; idx++ {
+		if err := build.CreateEntry(context.Background(), entry); err != nil {
+			t.Fatalf("CreateEntry() error = %v", err)
+		}
+	}
+
+	backups := make(map[string]struct{}, len(build.Report.Changes))
+	for _, change := range build.Report.Changes {
+		if change.Action != ActionBackedUp {
+			t.Fatalf("expected action %q, got %q", ActionBackedUp, change.Action)
+		}
+		if _, exists := backups[change.Backup]; exists {
+			t.Fatalf("backup %q was written twice", change.Backup)
+		}
+		backups[change.Backup] = struct{}{}
+	}
+
+	if got := readFile(t, build.FS, build.Report.Changes[0].Backup); got != "old" {
+		t.Fatalf("expected first backup content %q, got %q", "old", got)
+	}
+}
+
+func TestBuilder_CreateEntry_RootedFS(t *testing.T) {
+	t.Parallel()
+
+	// A rooted filesystem must not allow entries to escape the project directory
+	build := New(afero.NewBasePathFs(afero.NewMemMapFs(), "/project"), "", nil)
+
+	err := build.CreateEntry(context.Background(), &domain.DirEntry{Path: "../outside.txt"})
+	if err == nil {
+		t.Fatalf("expected an error for an entry outside of the project directory")
+	}
+}
@@ -0,0 +1,135 @@
+package projects
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/afero"
+)
+
+// MarkerFile is the name of the file that proji writes into the root directory of every tracked project. It identifies
+// the project, even after its directory was moved or renamed.
+const MarkerFile = ".proji"
+
+// Marker is the content of a project's marker file.
+type Marker struct {
+	ID      string `toml:"id"`
+	Package string `toml:"package"`
+}
+
+// WriteMarker writes the marker file into the directory dir.
+func WriteMarker(fs afero.Fs, dir string, marker *Marker) error {
+	if marker == nil || marker.ID == "" {
+		return errors.New("marker has no project id")
+	}
+
+	data, err := toml.Marshal(marker)
+	if err != nil {
+		return errors.Wrap(err, "marshal marker")
+	}
+
+	path := filepath.Join(dir, MarkerFile)
+	if err = afero.WriteFile(fs, path, data, 0o644); err != nil {
+		return errors.Wrapf(err, "write marker %q", path)
+	}
+
+	return nil
+}
+
+// ReadMarker reads the marker file from the directory dir. It returns an error that wraps os.ErrNotExist if the
+// directory holds no marker file.
+func ReadMarker(fs afero.Fs, dir string) (*Marker, error) {
+	path := filepath.Join(dir, MarkerFile)
+
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read marker %q", path)
+	}
+
+	marker := &Marker{}
+	if err = toml.Unmarshal(data, marker); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal marker %q", path)
+	}
+	if marker.ID == "" {
+		return nil, errors.Newf("marker %q has no project id", path)
+	}
+
+	return marker, nil
+}
+
+// skipScanDirs holds directories that are never descended into while scanning for markers. They tend to be huge and
+// never hold projects of their own.
+var skipScanDirs = map[string]struct{}{
+	".git":         {},
+	"node_modules": {},
+	"vendor":       {},
+}
+
+// ScanMarkers searches the given root directories for project markers. Directories deeper than maxDepth levels below a
+// root are not searched; a maxDepth smaller than one means no limit. It returns the directories that contain a marker,
+// grouped by project id. Unreadable directories and invalid markers are skipped.
+func ScanMarkers(ctx context.Context, fs afero.Fs, roots []string, maxDepth int) (map[string][]string, error) {
+	found := make(map[string][]string)
+	seen := make(map[string]struct{})
+
+	for _, root := range roots {
+		root = filepath.Clean(root)
+
+		err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				if path == root {
+					return err
+				}
+
+				return nil // Skip unreadable entries
+			}
+			if !info.IsDir() {
+				return nil
+			}
+
+			if path != root {
+				if _, skip := skipScanDirs[info.Name()]; skip {
+					return filepath.SkipDir
+				}
+			}
+
+			// Roots might overlap; every directory is only looked at once
+			if _, exists := seen[path]; exists {
+				return filepath.SkipDir
+			}
+			seen[path] = struct{}{}
+
+			if marker, err := ReadMarker(fs, path); err == nil {
+				found[marker.ID] = append(found[marker.ID], path)
+			}
+
+			if maxDepth > 0 && depth(root, path) >= maxDepth {
+				return filepath.SkipDir
+			}
+
+			return nil
+		})
+		if err != nil {
+			return found, errors.Wrapf(err, "scan %q", root)
+		}
+	}
+
+	return found, nil
+}
+
+// depth returns the number of path elements between root and path.
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
@@ -0,0 +1,76 @@
+package projects
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestMarker(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	dir := filepath.FromSlash("/code/api")
+	want := &Marker{ID: "cf1l3q4bvs0e0m0ibmcg", Package: "go"}
+
+	if _, err := ReadMarker(fs, dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ReadMarker() error = %v, want os.ErrNotExist", err)
+	}
+
+	if err := WriteMarker(fs, dir, &Marker{}); err == nil {
+		t.Fatal("WriteMarker() accepted a marker without project id")
+	}
+
+	if err := WriteMarker(fs, dir, want); err != nil {
+		t.Fatalf("WriteMarker() returned an unexpected error: %v", err)
+	}
+
+	got, err := ReadMarker(fs, dir)
+	if err != nil {
+		t.Fatalf("ReadMarker() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("ReadMarker() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanMarkers(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	markers := map[string]string{
+		"/code/api":                     "a",
+		"/code/archive/2020/api":        "a", // Copy of the same project
+		"/code/web":                     "b",
+		"/code/web/node_modules/pkg":    "c", // Skipped directory
+		"/code/deep/er/than/allowed/go": "d", // Too deep
+		"/other/cli":                    "e", // Not below a root
+	}
+	for dir, id := range markers {
+		if err := WriteMarker(fs, filepath.FromSlash(dir), &Marker{ID: id}); err != nil {
+			t.Fatalf("write marker into %q: %v", dir, err)
+		}
+	}
+	if err := afero.WriteFile(fs, filepath.FromSlash("/code/broken/"+MarkerFile), []byte("id = "), 0o644); err != nil {
+		t.Fatalf("write broken marker: %v", err)
+	}
+
+	roots := []string{filepath.FromSlash("/code"), filepath.FromSlash("/code/web")} // Overlapping roots
+	got, err := ScanMarkers(context.Background(), fs, roots, 3)
+	if err != nil {
+		t.Fatalf("ScanMarkers() returned an unexpected error: %v", err)
+	}
+
+	want := map[string][]string{
+		"a": {filepath.FromSlash("/code/api"), filepath.FromSlash("/code/archive/2020/api")},
+		"b": {filepath.FromSlash("/code/web")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("ScanMarkers() mismatch (-want +got):\n%s", diff)
+	}
+}
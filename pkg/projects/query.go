@@ -0,0 +1,257 @@
+package projects
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+// Filter selects projects. Empty fields don't filter.
+type Filter struct {
+	IDs           []string       // Project has one of these IDs; only nil doesn't filter
+	Packages      []string       // Project has one of these packages
+	Name          string         // Case-insensitive substring of the project name
+	NameRegex     *regexp.Regexp // Matches the project name
+	Tags          []string       // Project has all of these tags
+	CreatedAfter  time.Time      // Project was created at or after this time
+	CreatedBefore time.Time      // Project was created before this time
+	Exists        *bool          // Project path exists or not
+
+	// PathExists reports whether a project path exists. It defaults to checking the local filesystem.
+	PathExists func(path string) bool
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Match checks whether the project passes the filter.
+func (f *Filter) Match(project *domain.Project) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.IDs != nil && !containsFold(f.IDs, project.ID) {
+		return false
+	}
+	if len(f.Packages) > 0 && !containsFold(f.Packages, project.Package) {
+		return false
+	}
+	if f.Name != "" && !strings.Contains(strings.ToLower(project.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(project.Name) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !project.HasTag(tag) {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() && project.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !project.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	if f.Exists != nil {
+		exists := f.PathExists
+		if exists == nil {
+			exists = pathExists
+		}
+		if exists(project.Path) != *f.Exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterProjects returns the projects that pass the filter, keeping their order.
+func FilterProjects(projects []domain.Project, filter *Filter) []domain.Project {
+	filtered := make([]domain.Project, 0, len(projects))
+	for idx := range projects {
+		if filter.Match(&projects[idx]) {
+			filtered = append(filtered, projects[idx])
+		}
+	}
+
+	return filtered
+}
+
+// SortField is a field that projects can be sorted by.
+type SortField string
+
+// Fields that projects can be sorted by.
+const (
+	SortByID      SortField = "id"
+	SortByName    SortField = "name"
+	SortByPackage SortField = "package"
+	SortByPath    SortField = "path"
+	SortByCreated SortField = "created"
+	SortByUpdated SortField = "updated"
+)
+
+// ParseSortField parses the name of a sort field.
+func ParseSortField(s string) (SortField, error) {
+	field := SortField(strings.ToLower(strings.TrimSpace(s)))
+	switch field {
+	case SortByID, SortByName, SortByPackage, SortByPath, SortByCreated, SortByUpdated:
+		return field, nil
+	}
+
+	return "", errors.Newf("invalid sort field %q; valid fields are id, name, package, path, created and updated", s)
+}
+
+// less compares the given field of two projects. Strings are compared case-insensitively.
+func less(a, b *domain.Project, field SortField) bool {
+	switch field {
+	case SortByID:
+		return a.ID < b.ID
+	case SortByName:
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	case SortByPackage:
+		return strings.ToLower(a.Package) < strings.ToLower(b.Package)
+	case SortByPath:
+		return a.Path < b.Path
+	case SortByUpdated:
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	default:
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+}
+
+// SortProjects sorts the projects in place by the given field. Projects with equal fields keep their relative order.
+func SortProjects(projects []domain.Project, field SortField, descending bool) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		if descending {
+			return less(&projects[j], &projects[i], field)
+		}
+
+		return less(&projects[i], &projects[j], field)
+	})
+}
+
+// SearchResult is a project that matched a search query.
+type SearchResult struct {
+	Project *domain.Project
+	Score   int
+}
+
+// Weights of the fields that are searched; a match in the name counts more than a match in the description.
+const (
+	weightName        = 3
+	weightPath        = 2
+	weightDescription = 1
+)
+
+// Search fuzzy-matches the query against the name, path and description of the projects. Every whitespace separated
+// term of the query has to match at least one of the fields. Results are ordered by score, best match first.
+func Search(projects []domain.Project, query string) []*SearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	results := make([]*SearchResult, 0)
+	for idx := range projects {
+		project := &projects[idx]
+
+		description := ""
+		if project.Description != nil {
+			description = *project.Description
+		}
+
+		total, matched := 0, true
+		for _, term := range terms {
+			best := 0
+			for _, field := range []struct {
+				text   string
+				weight int
+			}{
+				{text: project.Name, weight: weightName},
+				{text: project.Path, weight: weightPath},
+				{text: description, weight: weightDescription},
+			} {
+				if score := fuzzyScore(term, field.text); score*field.weight > best {
+					best = score * field.weight
+				}
+			}
+
+			if best == 0 {
+				matched = false
+				break
+			}
+			total += best
+		}
+
+		if matched {
+			results = append(results, &SearchResult{Project: project, Score: total})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	return results
+}
+
+// fuzzyScore scores how well the lowercase term matches the text. All characters of the term have to appear in the
+// text in order; consecutive characters, characters at the start of words, exact substrings and prefixes score
+// higher. It returns zero if the term doesn't match.
+func fuzzyScore(term, text string) int {
+	if term == "" || text == "" {
+		return 0
+	}
+
+	query := []rune(term)
+	target := []rune(strings.ToLower(text))
+
+	score, queryIdx, prev := 0, 0, -2
+	for idx := 0; idx < len(target) && queryIdx < len(query); idx++ {
+		if target[idx] != query[queryIdx] {
+			continue
+		}
+
+		score++
+		if prev == idx-1 {
+			score += 5 // Consecutive characters
+		}
+		if idx == 0 || !unicode.IsLetter(target[idx-1]) && !unicode.IsDigit(target[idx-1]) {
+			score += 3 // Start of a word
+		}
+
+		prev = idx
+		queryIdx++
+	}
+
+	if queryIdx < len(query) {
+		return 0
+	}
+
+	switch lowerText := string(target); {
+	case strings.HasPrefix(lowerText, term):
+		score += 15 // Exact prefix
+	case strings.Contains(lowerText, term):
+		score += 10 // Exact substring
+	}
+
+	return score
+}
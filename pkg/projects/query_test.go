@@ -0,0 +1,161 @@
+package projects
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+	"github.com/nikoksr/proji/pkg/pointer"
+)
+
+func testProjects() []domain.Project {
+	day := func(d int) time.Time { return time.Date(2023, time.March, d, 12, 0, 0, 0, time.UTC) }
+
+	return []domain.Project{
+		{
+			ID: "1", Name: "billing-api", Package: "go", Path: "/code/client-a/billing-api", Tags: []string{"client-a"},
+			Description: pointer.To("Invoices and payments"), CreatedAt: day(3), UpdatedAt: day(9),
+		},
+		{ID: "2", Name: "Website", Package: "web", Path: "/code/client-b/website", CreatedAt: day(1), UpdatedAt: day(2)},
+		{
+			ID: "3", Name: "api-gateway", Package: "go", Path: "/gone/api-gateway", Tags: []string{"client-b", "wip"},
+			CreatedAt: day(5), UpdatedAt: day(5),
+		},
+	}
+}
+
+func ids(projects []domain.Project) []string {
+	result := make([]string, 0, len(projects))
+	for _, project := range projects {
+		result = append(result, project.ID)
+	}
+
+	return result
+}
+
+func TestFilterProjects(t *testing.T) {
+	t.Parallel()
+
+	exists := func(path string) bool { return path != "/gone/api-gateway" }
+
+	cases := []struct {
+		name   string
+		filter *Filter
+		want   []string
+	}{
+		{name: "nil filter", filter: nil, want: []string{"1", "2", "3"}},
+		{name: "ids", filter: &Filter{IDs: []string{"3", "1"}}, want: []string{"1", "3"}},
+		{name: "empty ids", filter: &Filter{IDs: []string{}}, want: []string{}},
+		{name: "package", filter: &Filter{Packages: []string{"GO"}}, want: []string{"1", "3"}},
+		{name: "name substring", filter: &Filter{Name: "API"}, want: []string{"1", "3"}},
+		{name: "name regex", filter: &Filter{NameRegex: regexp.MustCompile(`^api-`)}, want: []string{"3"}},
+		{name: "tags", filter: &Filter{Tags: []string{"client-b", "wip"}}, want: []string{"3"}},
+		{
+			name: "created range",
+			filter: &Filter{
+				CreatedAfter:  time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2023, time.March, 5, 12, 0, 0, 0, time.UTC),
+			},
+			want: []string{"1"},
+		},
+		{name: "path exists", filter: &Filter{Exists: pointer.To(true), PathExists: exists}, want: []string{"1", "2"}},
+		{name: "path missing", filter: &Filter{Exists: pointer.To(false), PathExists: exists}, want: []string{"3"}},
+		{name: "combined", filter: &Filter{Packages: []string{"go"}, Tags: []string{"client-a"}}, want: []string{"1"}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ids(FilterProjects(testProjects(), tc.filter))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("FilterProjects() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSortProjects(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		field      string
+		descending bool
+		want       []string
+		wantErr    bool
+	}{
+		{field: "name", want: []string{"3", "1", "2"}},
+		{field: "Package", want: []string{"1", "3", "2"}},
+		{field: "path", descending: true, want: []string{"3", "2", "1"}},
+		{field: "created", want: []string{"2", "1", "3"}},
+		{field: "updated", descending: true, want: []string{"1", "3", "2"}},
+		{field: "size", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.field, func(t *testing.T) {
+			t.Parallel()
+
+			field, err := ParseSortField(tc.field)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSortField() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			projects := testProjects()
+			SortProjects(projects, field, tc.descending)
+			if diff := cmp.Diff(tc.want, ids(projects)); diff != "" {
+				t.Fatalf("SortProjects() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{}},
+		{query: "api", want: []string{"3", "1"}},            // Name starts with the term
+		{query: "bapi", want: []string{"1"}},                // Fuzzy match
+		{query: "payments", want: []string{"1"}},            // Description
+		{query: "client-b", want: []string{"2", "1"}},       // Exact matches rank above fuzzy ones
+		{query: "client-b web", want: []string{"2"}},        // All terms have to match
+		{query: "gateway payments", want: []string{}},       // Terms match different projects
+		{query: "zzz", want: []string{}},                    // No match
+		{query: "WEBSITE", want: []string{"2"}},             // Case-insensitive
+		{query: "  billing   api ", want: []string{"1"}},    // Surrounding whitespace
+		{query: "gw", want: []string{"3"}},                  // Word starts
+		{query: "code", want: []string{"1", "2"}},           // Equal scores keep their order
+		{query: "/gone/api-gateway", want: []string{"3"}},   // Full path
+		{query: "invoices payments", want: []string{"1"}},   // Multiple terms in one field
+		{query: "api-gateway wip", want: []string{}},        // Tags are not searched
+		{query: "billing-api billing", want: []string{"1"}}, // Repeated terms
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]string, 0)
+			for _, result := range Search(testProjects(), tc.query) {
+				got = append(got, result.Project.ID)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Search(%q) mismatch (-want +got):\n%s", tc.query, diff)
+			}
+		})
+	}
+}
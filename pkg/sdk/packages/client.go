@@ -3,6 +3,7 @@ package packages
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/cockroachdb/errors"
 
@@ -63,3 +64,28 @@ func (c *Client) Update(ctx context.Context, pkg *domain.PackageUpdate) error {
 func (c *Client) Remove(ctx context.Context, label string) error {
 	return c.Backend.Call(ctx, http.MethodDelete, "/api/v1/packages/"+label, c.Key, nil, nil)
 }
+
+// History fetches all revisions of a package from the remote server, oldest first.
+func (c *Client) History(ctx context.Context, label string) ([]domain.PackageRevision, error) {
+	if label == "" {
+		return nil, errors.New("label is required")
+	}
+
+	var history []domain.PackageRevision
+	err := c.Backend.Call(ctx, http.MethodGet, "/api/v1/packages/"+label+"/revisions", c.Key, nil, &history)
+
+	return history, err
+}
+
+// GetRevision fetches a single revision of a package from the remote server.
+func (c *Client) GetRevision(ctx context.Context, label string, revision int) (domain.PackageRevision, error) {
+	if label == "" {
+		return domain.PackageRevision{}, errors.New("label is required")
+	}
+
+	var stored domain.PackageRevision
+	path := "/api/v1/packages/" + label + "/revisions/" + strconv.Itoa(revision)
+	err := c.Backend.Call(ctx, http.MethodGet, path, c.Key, nil, &stored)
+
+	return stored, err
+}
@@ -24,9 +24,13 @@ var defaultMissingKeyFn = func(key string) (string, error) {
 // templates. Start- and end-tags are used to mark keys. The default start- and end-tags are '{{' and '}}'.
 // MissingKeyFn is a function that is called when a key is not found in the template. It is expected to return
 // the value for the key.
+// Values holds the values of all keys that were resolved so far, indexed by their normalized key. It is shared between
+// all templates that get rendered by the engine, so that a value is only asked for once. It may be pre-filled to
+// provide values upfront.
 type TemplateEngine struct {
 	StartTag, EndTag string
 	MissingKeyFn     MissingKeyFn
+	Values           map[string]string
 }
 
 const (
@@ -47,6 +51,19 @@ func NewEngine(startTag, endTag string) *TemplateEngine {
 		StartTag:     startTag,
 		EndTag:       endTag,
 		MissingKeyFn: defaultMissingKeyFn,
+		Values:       make(map[string]string),
+	}
+}
+
+// SetValues pre-fills the engine with the given values. Keys get normalized, so that they match the keys used in
+// templates.
+func (t *TemplateEngine) SetValues(values map[string]string) {
+	if t.Values == nil {
+		t.Values = make(map[string]string, len(values))
+	}
+
+	for key, value := range values {
+		t.Values[normalizeKey(key)] = value
 	}
 }
 
@@ -77,14 +94,13 @@ func (t *TemplateEngine) render(ctx context.Context, w io.Writer, tmpl *fasttemp
 	logger := simplog.FromContext(ctx)
 
 	var err error
-	keys := make(map[string]string)
 
 	written, err := tmpl.ExecuteFunc(w, func(w io.Writer, key string) (int, error) {
 		printableKey := humanReadableKey(key)
 		key = normalizeKey(key)
 
 		logger.Debugf("checking value for template key: %q", key)
-		value, exists := keys[key]
+		value, exists := t.Values[key]
 		if !exists {
 			logger.Debugf("value for template key %q not previously defined", key)
 			if value, err = t.MissingKeyFn(printableKey); err != nil {
@@ -93,7 +109,7 @@ func (t *TemplateEngine) render(ctx context.Context, w io.Writer, tmpl *fasttemp
 		}
 
 		logger.Debugf("using value %q for template key %q", value, key)
-		keys[key] = value
+		t.Values[key] = value
 
 		return w.Write([]byte(value))
 	})
@@ -114,6 +130,9 @@ func (t *TemplateEngine) parse(ctx context.Context, w io.Writer, data []byte) er
 		logger.Debugf("using default missing key function")
 		t.MissingKeyFn = defaultMissingKeyFn
 	}
+	if t.Values == nil {
+		t.Values = make(map[string]string)
+	}
 
 	// Parse the template
 	logger.Debugf("parsing %d bytes of template data", len(data))
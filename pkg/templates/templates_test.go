@@ -32,6 +32,7 @@ func TestNewEngine(t *testing.T) {
 				StartTag:     "%{{",
 				EndTag:       "}}%",
 				MissingKeyFn: defaultMissingKeyFn,
+				Values:       map[string]string{},
 			},
 		},
 		{
@@ -44,6 +45,7 @@ func TestNewEngine(t *testing.T) {
 				StartTag:     "%{{",
 				EndTag:       "}}%",
 				MissingKeyFn: defaultMissingKeyFn,
+				Values:       map[string]string{},
 			},
 		},
 		{
@@ -56,6 +58,7 @@ func TestNewEngine(t *testing.T) {
 				StartTag:     "!!",
 				EndTag:       "??",
 				MissingKeyFn: defaultMissingKeyFn,
+				Values:       map[string]string{},
 			},
 		},
 	}
@@ -480,3 +483,39 @@ John Doe.
 		})
 	}
 }
+
+func TestTemplateEngine_SharedValues(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	engine := NewEngine("", "")
+	engine.MissingKeyFn = func(key string) (string, error) {
+		calls++
+		if key == "Author" {
+			return "John Doe", nil
+		}
+
+		return "", errors.Newf("unexpected key: %s", key)
+	}
+	engine.SetValues(map[string]string{"Project-Name": "Proji"})
+
+	// Values have to be shared between all templates that get rendered by the engine.
+	for _, data := range []string{"%{{project_name}}% by %{{author}}%", "%{{Project Name}}% by %{{AUTHOR}}%"} {
+		got, err := engine.ParseToString(context.Background(), data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "Proji by John Doe"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("MissingKeyFn called %d times, want 1", calls)
+	}
+
+	want := map[string]string{"projectname": "Proji", "author": "John Doe"}
+	if diff := cmp.Diff(want, engine.Values); diff != "" {
+		t.Fatalf("values mismatch (-want +got):\n%s", diff)
+	}
+}
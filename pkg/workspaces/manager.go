@@ -0,0 +1,63 @@
+// Package workspaces manages workspaces, named groups of related projects.
+package workspaces
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/nikoksr/proji/pkg/api/v1/domain"
+)
+
+// Manager is an interface for managing workspaces.
+type Manager interface {
+	Fetch(ctx context.Context) ([]domain.Workspace, error)
+	GetByName(ctx context.Context, name string) (domain.Workspace, error)
+	Store(ctx context.Context, workspace *domain.WorkspaceAdd) error
+	Update(ctx context.Context, workspace *domain.WorkspaceUpdate) error
+	Remove(ctx context.Context, name string) error
+}
+
+// manager is the default implementation of the Manager interface. Like projects, workspaces are only stored locally.
+type manager struct {
+	service domain.WorkspaceService
+}
+
+// Compile-time check to ensure that manager implements the Manager interface.
+var _ Manager = &manager{}
+
+// NewManager creates a new manager. It requires a domain.WorkspaceService to be set.
+func NewManager(service domain.WorkspaceService) (Manager, error) {
+	if service == nil {
+		return nil, errors.New("service is required")
+	}
+
+	return &manager{
+		service: service,
+	}, nil
+}
+
+// Fetch fetches all workspaces from the local storage.
+func (m *manager) Fetch(ctx context.Context) ([]domain.Workspace, error) {
+	return m.service.Fetch(ctx)
+}
+
+// GetByName fetches a workspace by its name.
+func (m *manager) GetByName(ctx context.Context, name string) (domain.Workspace, error) {
+	return m.service.GetByName(ctx, name)
+}
+
+// Store stores a workspace in the local storage.
+func (m *manager) Store(ctx context.Context, workspace *domain.WorkspaceAdd) error {
+	return m.service.Store(ctx, workspace)
+}
+
+// Update updates a workspace in the local storage.
+func (m *manager) Update(ctx context.Context, workspace *domain.WorkspaceUpdate) error {
+	return m.service.Update(ctx, workspace)
+}
+
+// Remove removes a workspace from the local storage.
+func (m *manager) Remove(ctx context.Context, name string) error {
+	return m.service.Remove(ctx, name)
+}